@@ -0,0 +1,503 @@
+package parse
+
+import (
+    "fmt"
+    "reflect"
+    "strings"
+    "sync"
+    "time"
+
+    "net/http"
+
+    "github.com/dsoprea/go-logging"
+)
+
+const (
+    // BindTagName is the struct-tag consulted by Bind().
+    BindTagName = "multiparse"
+)
+
+var (
+    timeType = reflect.TypeOf(time.Time{})
+
+    // bindPlans caches the fields of each struct type that has been bound
+    // (reflect.Type to []bindField). Only valid types are cached.
+    bindPlans sync.Map
+)
+
+// bindField describes a single tagged struct field.
+type bindField struct {
+    // path is the dotted Go field path (e.g. "Filter.Limit").
+    path string
+
+    index []int
+
+    source string
+    name string
+    kindName string
+    required bool
+
+    // textType is set if the field is unmarshaled from a string rather than
+    // parsed as a kind. See isTextType().
+    textType reflect.Type
+
+    // defaultValue is the parsed "default" option, if any, and defaultRaw
+    // is the option as given.
+    defaultValue interface{}
+    defaultRaw string
+}
+
+// kindNameForType determines the kind to parse for a Go type when one is
+// not given explicitly.
+func kindNameForType(t reflect.Type) (kindName string, found bool) {
+    if t == timeType {
+        return "rfc3339", true
+    }
+
+    switch t.Kind() {
+    case reflect.Bool:
+        return "bool", true
+    case reflect.Int:
+        return "int64", true
+    case reflect.Uint:
+        return "uint64", true
+    }
+
+    kindName, found = KindNameMap[t.Kind()]
+    return kindName, found
+}
+
+// parseBindTag parses a tag like "query=id,kind=uint64,required".
+func parseBindTag(path string, t reflect.Type, tag string) (bf bindField, err error) {
+    bf.path = path
+
+    defaultRaw := ""
+    hasDefault := false
+
+    for _, part := range strings.Split(tag, ",") {
+        part = strings.TrimSpace(part)
+        if part == "" {
+            continue
+        }
+
+        key, value, _ := strings.Cut(part, "=")
+
+        switch key {
+        case SourceQuery, SourceBody, SourceHeader, SourceCookie, SourceJson:
+            if bf.source != "" {
+                return bf, fmt.Errorf("field [%s] has more than one source", path)
+            } else if value == "" {
+                return bf, fmt.Errorf("field [%s] source [%s] has no name", path, key)
+            }
+
+            bf.source = key
+            bf.name = value
+        case "kind":
+            bf.kindName = value
+        case "required":
+            bf.required = true
+        case "default":
+            defaultRaw = value
+            hasDefault = true
+        default:
+            return bf, fmt.Errorf("field [%s] tag option [%s] not valid", path, key)
+        }
+    }
+
+    if bf.source == "" {
+        return bf, fmt.Errorf("field [%s] has no source", path)
+    } else if hasDefault == true && bf.required == true {
+        return bf, fmt.Errorf("field [%s] is required but has a default", path)
+    }
+
+    err = bf.resolveKind(t, defaultRaw, hasDefault)
+    return bf, err
+}
+
+// resolveKind infers the kind of the field from its type, if it was not
+// given, and validates it. A default is parsed here, so that an invalid one
+// is reported with the other tag errors.
+func (bf *bindField) resolveKind(t reflect.Type, defaultRaw string, hasDefault bool) (err error) {
+    path := bf.path
+
+    if t.Kind() == reflect.Ptr {
+        t = t.Elem()
+    }
+
+    if bf.kindName == "" && isTextType(t) == true {
+        bf.kindName = "string"
+        bf.textType = t
+    } else {
+        if bf.kindName == "" {
+            kindName, found := kindNameForType(t)
+            if found == false {
+                return fmt.Errorf("field [%s] kind can not be inferred from type [%s]", path, t)
+            }
+
+            bf.kindName = kindName
+        }
+
+        zeroType, found := DefaultRegistry.kindType(bf.kindName)
+        if found == false {
+            return fmt.Errorf("field [%s] kind [%s] not valid", path, bf.kindName)
+        } else if isStorable(zeroType, t) == false {
+            return fmt.Errorf("field [%s] kind [%s] can not be stored in type [%s]", path, bf.kindName, t)
+        }
+    }
+
+    if hasDefault == true {
+        bf.defaultRaw = defaultRaw
+
+        bf.defaultValue, err = bf.parseDefault(defaultRaw)
+        if err != nil {
+            return fmt.Errorf("field [%s] default [%s] not valid: %w", path, defaultRaw, err)
+        }
+    }
+
+    return nil
+}
+
+// parseDefault parses a default from a struct-tag as the kind of the field.
+func (bf *bindField) parseDefault(defaultRaw string) (value interface{}, err error) {
+    if bf.textType != nil {
+        return unmarshalText(bf.textType, defaultRaw)
+    }
+
+    return ParseE(defaultRaw, bf.kindName)
+}
+
+// collectBindFields finds the tagged fields of the given struct type,
+// descending into untagged struct fields.
+func collectBindFields(t reflect.Type, prefix string, index []int, fields []bindField) ([]bindField, error) {
+    for i := 0; i < t.NumField(); i++ {
+        sf := t.Field(i)
+        if sf.IsExported() == false {
+            continue
+        }
+
+        path := sf.Name
+        if prefix != "" {
+            path = prefix + "." + sf.Name
+        }
+
+        fieldIndex := make([]int, len(index) + 1)
+        copy(fieldIndex, index)
+        fieldIndex[len(index)] = i
+
+        tag, found := sf.Tag.Lookup(BindTagName)
+        if found == false {
+            if sf.Type.Kind() == reflect.Struct && sf.Type != timeType {
+                var err error
+                fields, err = collectBindFields(sf.Type, path, fieldIndex, fields)
+                if err != nil {
+                    return nil, err
+                }
+            }
+
+            continue
+        } else if tag == "-" {
+            continue
+        }
+
+        bf, err := parseBindTag(path, sf.Type, tag)
+        if err != nil {
+            return nil, err
+        }
+
+        bf.index = fieldIndex
+        fields = append(fields, bf)
+    }
+
+    return fields, nil
+}
+
+// bindPlan returns the tagged fields of the given struct type. They are
+// collected once per type.
+func bindPlan(t reflect.Type) (fields []bindField, err error) {
+    if cached, found := bindPlans.Load(t); found == true {
+        return cached.([]bindField), nil
+    }
+
+    fields, err = collectBindFields(t, "", nil, nil)
+    if err != nil {
+        return nil, err
+    }
+
+    bindPlans.Store(t, fields)
+
+    return fields, nil
+}
+
+// PrepareBind validates the struct-tags (including any defaults) of the
+// struct that `dst` points to and caches them for Bind(). Call it at startup
+// so that a struct that can not be bound fails immediately rather than when
+// the first request is served. It panics on failure.
+func PrepareBind(dst interface{}) {
+    err := PrepareBindE(dst)
+    log.PanicIf(err)
+}
+
+// PrepareBindE is the error-returning variant of PrepareBind.
+func PrepareBindE(dst interface{}) (err error) {
+    v, err := structValue(dst)
+    if err != nil {
+        return err
+    }
+
+    _, err = bindPlan(v.Type())
+    return err
+}
+
+// requestBinder reads values for tagged fields from a single request. The
+// JSON body is decoded at most once.
+type requestBinder struct {
+    r *http.Request
+    jrp *JsonRequestParser
+
+    // jsonDecoded is set once decoding the JSON body has been attempted.
+    jsonDecoded bool
+
+    // sources are created as they are first required, by source name.
+    sources map[string]Source
+}
+
+func (rb *requestBinder) get(bf bindField) (value interface{}, err error) {
+    source, err := rb.source(bf.source)
+    if err != nil {
+        return nil, err
+    } else if source == nil {
+        // The JSON body could not be decoded. This was already reported.
+        return nil, nil
+    }
+
+    value, _, err = getField(source, bf)
+    return value, err
+}
+
+// source returns the named source for the request. The JSON body is decoded
+// at most once.
+func (rb *requestBinder) source(name string) (source Source, err error) {
+    if source, found := rb.sources[name]; found == true {
+        return source, nil
+    }
+
+    switch name {
+    case SourceQuery:
+        source = NewQuerySource(rb.r)
+    case SourceBody:
+        source = NewBodySource(rb.r)
+    case SourceHeader:
+        source = NewHeaderSource(rb.r)
+    case SourceCookie:
+        source = NewCookieSource(rb.r)
+    case SourceJson:
+        if rb.jsonDecoded == true {
+            return nil, nil
+        }
+
+        rb.jsonDecoded = true
+
+        rb.jrp, err = NewJsonRequestParserE(rb.r)
+        if err != nil {
+            return nil, err
+        }
+
+        source = rb.jrp
+    default:
+        return nil, fmt.Errorf("source [%s] not valid", name)
+    }
+
+    rb.sources[name] = source
+
+    return source, nil
+}
+
+// getField looks up the value of the field in the source. Text types are
+// unmarshaled from the string value. `origin` is where the value was found
+// (see WithOrigin()).
+func getField(source Source, bf bindField) (value interface{}, origin Origin, err error) {
+    valueRaw, present, searched := lookupOrigin(source, bf.name)
+    return parseField(bf, searched, valueRaw, present)
+}
+
+// parseField parses a value that was looked up for the field. `searched` is
+// where the value was found or, if it is absent, the source that was
+// searched. `origin` is as for getField().
+func parseField(bf bindField, searched Origin, valueRaw interface{}, present bool) (value interface{}, origin Origin, err error) {
+    value, err = resolve(searched, bf.kindName, bf.required, valueRaw, present, []Option { WithOrigin(&origin) })
+    if err != nil || value == nil || bf.textType == nil {
+        return value, origin, err
+    }
+
+    s := value.(string)
+
+    value, err = unmarshalText(bf.textType, s)
+    if err != nil {
+        pe := newParseError("", s, classifyError(err), err, "")
+        return nil, origin, pe.withField(origin.Source, origin.Name)
+    }
+
+    return value, origin, nil
+}
+
+// isStorable indicates whether values of type `from` may be converted to
+// type `to` without changing their meaning (e.g. not an integer to a
+// string) or losing range (e.g. not a uint64 to a uint8, an int64 to a
+// uint32 or a float64 to an int).
+func isStorable(from, to reflect.Type) bool {
+    if from.ConvertibleTo(to) == false {
+        return false
+    }
+
+    fromClass, fromBits := numericClass(from)
+    toClass, toBits := numericClass(to)
+
+    if fromClass != toClass {
+        return false
+    } else if fromClass != reflect.Invalid && fromBits > toBits {
+        return false
+    }
+
+    return (from.Kind() == reflect.String) == (to.Kind() == reflect.String)
+}
+
+// numericClass returns the kind that represents the family of a numeric type
+// (Int, Uint or Float64) and its size. int and uint count as 64 bits since
+// those are the kinds that are inferred for them. Non-numeric types return
+// Invalid.
+func numericClass(t reflect.Type) (class reflect.Kind, bits int) {
+    switch t.Kind() {
+    case reflect.Int:
+        return reflect.Int, 64
+    case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+        return reflect.Int, t.Bits()
+    case reflect.Uint:
+        return reflect.Uint, 64
+    case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+        return reflect.Uint, t.Bits()
+    case reflect.Float32, reflect.Float64:
+        return reflect.Float64, t.Bits()
+    }
+
+    return reflect.Invalid, 0
+}
+
+// setField stores a parsed value into a field, converting to named types and
+// allocating pointers as required.
+func setField(fv reflect.Value, value interface{}) {
+    if fv.Kind() == reflect.Ptr {
+        elem := reflect.New(fv.Type().Elem())
+        setField(elem.Elem(), value)
+        fv.Set(elem)
+
+        return
+    }
+
+    fv.Set(reflect.ValueOf(value).Convert(fv.Type()))
+}
+
+// structValue validates that `dst` is a non-nil pointer to a struct.
+func structValue(dst interface{}) (v reflect.Value, err error) {
+    v = reflect.ValueOf(dst)
+    if v.Kind() != reflect.Ptr || v.IsNil() == true || v.Elem().Kind() != reflect.Struct {
+        return v, fmt.Errorf("destination must be a non-nil pointer to a struct: [%T]", dst)
+    }
+
+    return v.Elem(), nil
+}
+
+// Bind populates the tagged fields of the struct that `dst` points to from
+// the request. It panics on failure.
+func Bind(r *http.Request, dst interface{}) {
+    err := BindE(r, dst)
+    log.PanicIf(err)
+}
+
+// BindE is the error-returning variant of Bind. Fields are tagged like:
+//
+//     ID uint64 `multiparse:"query=id,required"`
+//     Token string `multiparse:"header=X-Token"`
+//     Limit *int32 `multiparse:"json=limit,kind=int32"`
+//
+// The source is one of "query", "body" (form-encoded), "header", "cookie",
+// or "json". The kind is inferred from the field type when omitted. Fields
+// whose type implements encoding.TextUnmarshaler or flag.Value (through a
+// pointer) are unmarshaled from the string value instead. Optional
+// fields that are absent are left untouched unless they have a default
+// (e.g. "default=50"; it can not contain a comma). Untagged struct fields are
+// descended into. The first failure is returned.
+func BindE(r *http.Request, dst interface{}) (err error) {
+    return bindRequest(r, dst, false)
+}
+
+// BindAll is like Bind but reports every invalid field at once. It panics
+// with a ParseErrors on failure.
+func BindAll(r *http.Request, dst interface{}) {
+    err := BindAllE(r, dst)
+    log.PanicIf(err)
+}
+
+// BindAllE is like BindE but parses every field before reporting. Parse
+// failures are returned together as a ParseErrors, in field order. An
+// invalid destination or struct-tag is still returned immediately.
+func BindAllE(r *http.Request, dst interface{}) (err error) {
+    return bindRequest(r, dst, true)
+}
+
+func bindRequest(r *http.Request, dst interface{}, collect bool) (err error) {
+    v, err := structValue(dst)
+    if err != nil {
+        return err
+    }
+
+    fields, err := bindPlan(v.Type())
+    if err != nil {
+        return err
+    }
+
+    rb := &requestBinder{
+        r: r,
+        sources: make(map[string]Source),
+    }
+
+    var pes ParseErrors
+
+    for _, bf := range fields {
+        value, err := rb.get(bf)
+        if err != nil {
+            pe, ok := err.(*ParseError)
+            if ok == false {
+                return err
+            }
+
+            pe = pe.withPath(bf.path)
+            if collect == false {
+                return pe
+            }
+
+            pes = append(pes, pe)
+            continue
+        } else if value == nil {
+            if bf.defaultValue == nil {
+                continue
+            }
+
+            // The plan is shared by every bind, so the default is parsed
+            // again rather than shared. Otherwise, changing a field of a
+            // reference type (e.g. a net.IP) would change the default. It
+            // was already validated.
+            value, err = bf.parseDefault(bf.defaultRaw)
+            if err != nil {
+                return err
+            }
+        }
+
+        setField(v.FieldByIndex(bf.index), value)
+    }
+
+    if len(pes) > 0 {
+        return pes
+    }
+
+    return nil
+}
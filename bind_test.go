@@ -0,0 +1,421 @@
+package parse
+
+import (
+    "testing"
+    "errors"
+    "strings"
+    "time"
+
+    "net"
+    "net/http"
+)
+
+type testAccountId uint64
+
+type testBindFilter struct {
+    Limit *int32 `multiparse:"query=limit"`
+    Mask uint16 `multiparse:"query=mask,kind=hex16"`
+}
+
+type testBindRequest struct {
+    AccountId testAccountId `multiparse:"query=account_id,required"`
+    Token string `multiparse:"header=X-Token"`
+    Session string `multiparse:"cookie=session"`
+    Since time.Time `multiparse:"query=since"`
+    Enabled bool `multiparse:"query=enabled"`
+    Ignored string `multiparse:"-"`
+
+    Filter testBindFilter
+
+    unexported string
+}
+
+func TestBindE(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com?account_id=123&limit=-5&mask=FF0F&since=2016-11-08T19:32:29Z&enabled=true", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    req.Header.Set("X-Token", "abc")
+    req.AddCookie(&http.Cookie{ Name: "session", Value: "def" })
+
+    tbr := testBindRequest{}
+
+    err = BindE(req, &tbr)
+    if err != nil {
+        t.Fatalf("Bind failed: [%s]", err)
+    }
+
+    if tbr.AccountId != 123 {
+        t.Fatalf("AccountId not correct: [%d]", tbr.AccountId)
+    } else if tbr.Token != "abc" {
+        t.Fatalf("Token not correct: [%s]", tbr.Token)
+    } else if tbr.Session != "def" {
+        t.Fatalf("Session not correct: [%s]", tbr.Session)
+    } else if tbr.Since.Equal(time.Date(2016, 11, 8, 19, 32, 29, 0, time.UTC)) == false {
+        t.Fatalf("Since not correct: [%s]", tbr.Since)
+    } else if tbr.Enabled != true {
+        t.Fatalf("Enabled not correct.")
+    } else if tbr.Filter.Limit == nil || *tbr.Filter.Limit != -5 {
+        t.Fatalf("Limit not correct: [%v]", tbr.Filter.Limit)
+    } else if tbr.Filter.Mask != 0xFF0F {
+        t.Fatalf("Mask not correct: [%x]", tbr.Filter.Mask)
+    }
+}
+
+func TestBindE_Optional_Miss(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com?account_id=123", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    tbr := testBindRequest{
+        Token: "original",
+    }
+
+    err = BindE(req, &tbr)
+    if err != nil {
+        t.Fatalf("Bind failed: [%s]", err)
+    }
+
+    if tbr.Token != "original" {
+        t.Fatalf("Absent optional field was overwritten: [%s]", tbr.Token)
+    } else if tbr.Filter.Limit != nil {
+        t.Fatalf("Absent optional pointer field was allocated.")
+    }
+}
+
+func TestBindE_Required_Miss(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    tbr := testBindRequest{}
+
+    err = BindE(req, &tbr)
+    if errors.Is(err, ErrMissing) == false {
+        t.Fatalf("Expected missing error: [%v]", err)
+    }
+}
+
+func TestBindE_Json(t *testing.T) {
+    type jsonRequest struct {
+        Name string `multiparse:"json=name,required"`
+        Count uint8 `multiparse:"json=count"`
+        Page uint32 `multiparse:"query=page"`
+    }
+
+    req, err := http.NewRequest("POST", "http://example.com?page=2", strings.NewReader(`{"name": "abc", "count": "12"}`))
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    jr := jsonRequest{}
+
+    err = BindE(req, &jr)
+    if err != nil {
+        t.Fatalf("Bind failed: [%s]", err)
+    }
+
+    if jr.Name != "abc" || jr.Count != 12 || jr.Page != 2 {
+        t.Fatalf("Bound values not correct: %v", jr)
+    }
+}
+
+func TestBindE_Body(t *testing.T) {
+    type formRequest struct {
+        Name string `multiparse:"body=name,required"`
+        Count uint8 `multiparse:"body=count"`
+        Page uint32 `multiparse:"query=page"`
+    }
+
+    req, err := http.NewRequest("POST", "http://example.com?page=2", strings.NewReader("name=abc&count=12"))
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+    fr := formRequest{}
+
+    err = BindE(req, &fr)
+    if err != nil {
+        t.Fatalf("Bind failed: [%s]", err)
+    }
+
+    if fr.Name != "abc" || fr.Count != 12 || fr.Page != 2 {
+        t.Fatalf("Bound values not correct: %v", fr)
+    }
+}
+
+func TestBindE_InvalidTag(t *testing.T) {
+    type invalidRequest struct {
+        Value string `multiparse:"query=value,kind=uint64"`
+    }
+
+    req, err := http.NewRequest("GET", "http://example.com", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    err = BindE(req, &invalidRequest{})
+    if err == nil || err.Error() != "field [Value] kind [uint64] can not be stored in type [string]" {
+        t.Fatalf("Expected error for invalid tag: [%v]", err)
+    }
+}
+
+func TestBindE_LossyKind(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    type wideRequest struct {
+        A uint8 `multiparse:"query=a,kind=uint64"`
+    }
+
+    type floatRequest struct {
+        B int `multiparse:"query=b,kind=float64"`
+    }
+
+    type signRequest struct {
+        C uint32 `multiparse:"query=c,kind=int64"`
+    }
+
+    type signNarrowRequest struct {
+        D int64 `multiparse:"query=d,kind=uint32"`
+    }
+
+    cases := []struct {
+        dst interface{}
+        expected string
+    }{
+        {&wideRequest{}, "field [A] kind [uint64] can not be stored in type [uint8]"},
+        {&floatRequest{}, "field [B] kind [float64] can not be stored in type [int]"},
+        {&signRequest{}, "field [C] kind [int64] can not be stored in type [uint32]"},
+        {&signNarrowRequest{}, "field [D] kind [uint32] can not be stored in type [int64]"},
+    }
+
+    for _, c := range cases {
+        err := BindE(req, c.dst)
+        if err == nil || err.Error() != c.expected {
+            t.Fatalf("Expected error [%s]: [%v]", c.expected, err)
+        }
+    }
+
+    type narrowRequest struct {
+        A uint64 `multiparse:"query=a,kind=uint8"`
+        B int `multiparse:"query=b,kind=int64"`
+        C float64 `multiparse:"query=c,kind=float32"`
+    }
+
+    req, err = http.NewRequest("GET", "http://example.com?a=255&b=-3&c=1.5", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    nr := narrowRequest{}
+
+    err = BindE(req, &nr)
+    if err != nil {
+        t.Fatalf("Bind failed: [%s]", err)
+    } else if nr.A != 255 || nr.B != -3 || nr.C != 1.5 {
+        t.Fatalf("Bound values not correct: %v", nr)
+    }
+}
+
+func TestBindE_InvalidDestination(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    err = BindE(req, testBindRequest{})
+    if err == nil {
+        t.Fatalf("Expected error for non-pointer destination.")
+    }
+}
+
+func TestBindAllE(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com?limit=abc&mask=FFFFF&enabled=true", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    tbr := testBindRequest{}
+
+    err = BindAllE(req, &tbr)
+
+    pes, ok := err.(ParseErrors)
+    if ok == false {
+        t.Fatalf("Expected ParseErrors: [%v]", err)
+    } else if len(pes) != 3 {
+        t.Fatalf("Expected three errors: %v", pes)
+    }
+
+    expected := []struct {
+        field string
+        name string
+        cause error
+    } {
+        { "AccountId", "account_id", ErrMissing },
+        { "Filter.Limit", "limit", ErrSyntax },
+        { "Filter.Mask", "mask", ErrRange },
+    }
+
+    for i, e := range expected {
+        pe := pes[i]
+        if pe.Field() != e.field || pe.Name() != e.name || pe.Source() != SourceQuery || pe.Cause() != e.cause {
+            t.Fatalf("Error (%d) not correct: [%s] [%s] [%s] [%v]", i, pe.Field(), pe.Name(), pe.Source(), pe.Cause())
+        }
+    }
+
+    if errors.Is(err, ErrRange) == false {
+        t.Fatalf("Wrapped errors not exposed.")
+    }
+
+    // Valid fields are still bound.
+    if tbr.Enabled != true {
+        t.Fatalf("Valid field was not bound.")
+    }
+}
+
+func TestBindAllE_Success(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com?account_id=123", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    tbr := testBindRequest{}
+
+    err = BindAllE(req, &tbr)
+    if err != nil {
+        t.Fatalf("Bind failed: [%v]", err)
+    } else if tbr.AccountId != 123 {
+        t.Fatalf("AccountId not correct: [%d]", tbr.AccountId)
+    }
+}
+
+func TestBindE_FieldPath(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com?account_id=123&limit=abc", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    err = BindE(req, &testBindRequest{})
+
+    pe, ok := err.(*ParseError)
+    if ok == false {
+        t.Fatalf("Expected ParseError: [%v]", err)
+    } else if pe.Field() != "Filter.Limit" {
+        t.Fatalf("Field not correct: [%s]", pe.Field())
+    }
+}
+
+func TestBindE_Default(t *testing.T) {
+    type request struct {
+        Limit uint32 `multiparse:"query=limit,default=50"`
+        Offset *int64 `multiparse:"query=offset,default=0"`
+        Order string `multiparse:"query=order,default=asc"`
+        Level testLevel `multiparse:"header=X-Level,default=low"`
+    }
+
+    req, err := http.NewRequest("GET", "http://example.com?order=desc", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    tr := request{}
+
+    err = BindE(req, &tr)
+    if err != nil {
+        t.Fatalf("Bind failed: [%s]", err)
+    }
+
+    if tr.Limit != 50 {
+        t.Fatalf("Limit not correct: [%d]", tr.Limit)
+    } else if tr.Offset == nil || *tr.Offset != 0 {
+        t.Fatalf("Offset not correct: [%v]", tr.Offset)
+    } else if tr.Order != "desc" {
+        t.Fatalf("Order not correct: [%s]", tr.Order)
+    } else if tr.Level != 1 {
+        t.Fatalf("Level not correct: [%d]", tr.Level)
+    }
+}
+
+func TestBindE_Default_NotShared(t *testing.T) {
+    type request struct {
+        Address net.IP `multiparse:"query=address,default=10.0.0.1"`
+    }
+
+    bind := func() request {
+        req, err := http.NewRequest("GET", "http://example.com", nil)
+        if err != nil {
+            t.Fatalf("Could not fabricate request: [%s]", err)
+        }
+
+        tr := request{}
+
+        err = BindE(req, &tr)
+        if err != nil {
+            t.Fatalf("Bind failed: [%s]", err)
+        }
+
+        return tr
+    }
+
+    first := bind()
+    first.Address[len(first.Address) - 1] = 99
+
+    if second := bind(); second.Address.Equal(net.IPv4(10, 0, 0, 1)) == false {
+        t.Fatalf("Changing a bound field changed the default: [%s]", second.Address)
+    }
+}
+
+func TestPrepareBindE(t *testing.T) {
+    type valid struct {
+        Limit uint32 `multiparse:"query=limit,default=50"`
+    }
+
+    if err := PrepareBindE(&valid{}); err != nil {
+        t.Fatalf("Prepare failed: [%s]", err)
+    }
+
+    type invalidDefault struct {
+        Limit uint8 `multiparse:"query=limit,default=300"`
+    }
+
+    err := PrepareBindE(&invalidDefault{})
+    if err == nil || strings.Contains(err.Error(), "default [300] not valid") == false {
+        t.Fatalf("Expected invalid-default error: [%v]", err)
+    } else if errors.Is(err, ErrRange) == false {
+        t.Fatalf("Expected range error: [%v]", err)
+    }
+
+    type requiredDefault struct {
+        Limit uint32 `multiparse:"query=limit,required,default=50"`
+    }
+
+    if err := PrepareBindE(&requiredDefault{}); err == nil {
+        t.Fatalf("Expected error for required field with a default.")
+    }
+
+    if err := PrepareBindE(valid{}); err == nil {
+        t.Fatalf("Expected error for non-pointer destination.")
+    }
+}
+
+func TestPrepareBind_Panic(t *testing.T) {
+    type invalidTag struct {
+        Limit uint32 `multiparse:"query=limit,unknown"`
+    }
+
+    defer func() {
+        if errRaw := recover(); errRaw == nil {
+            t.Fatalf("Expected panic for invalid tag.")
+        }
+    }()
+
+    PrepareBind(&invalidTag{})
+}
@@ -0,0 +1,274 @@
+package parse
+
+import (
+    "fmt"
+    "reflect"
+    "sort"
+    "time"
+
+    "github.com/dsoprea/go-logging"
+)
+
+// The per-kind capabilities. A parser only has to implement the ones for the
+// kinds that it supports. ParseE reports any other kind as
+// ErrUnsupportedKind. A parser that implements the whole Parser interface
+// (but not the capability for a kind) is also supported; its panics are
+// recovered and returned as errors, except for runtime errors (e.g. a nil
+// dereference), which are re-raised.
+
+// StringKindParser parses values as a "string".
+type StringKindParser interface {
+    StringE(value interface{}) (string, error)
+}
+
+// Int8KindParser parses values as an "int8".
+type Int8KindParser interface {
+    Int8E(value interface{}) (int8, error)
+}
+
+// Int16KindParser parses values as an "int16".
+type Int16KindParser interface {
+    Int16E(value interface{}) (int16, error)
+}
+
+// Int32KindParser parses values as an "int32".
+type Int32KindParser interface {
+    Int32E(value interface{}) (int32, error)
+}
+
+// Int64KindParser parses values as an "int64".
+type Int64KindParser interface {
+    Int64E(value interface{}) (int64, error)
+}
+
+// Uint8KindParser parses values as a "uint8".
+type Uint8KindParser interface {
+    Uint8E(value interface{}) (uint8, error)
+}
+
+// Uint16KindParser parses values as a "uint16".
+type Uint16KindParser interface {
+    Uint16E(value interface{}) (uint16, error)
+}
+
+// Uint32KindParser parses values as a "uint32".
+type Uint32KindParser interface {
+    Uint32E(value interface{}) (uint32, error)
+}
+
+// Uint64KindParser parses values as a "uint64".
+type Uint64KindParser interface {
+    Uint64E(value interface{}) (uint64, error)
+}
+
+// Hex8KindParser parses values as a "hex8".
+type Hex8KindParser interface {
+    Hex8E(value interface{}) (uint8, error)
+}
+
+// Hex16KindParser parses values as a "hex16".
+type Hex16KindParser interface {
+    Hex16E(value interface{}) (uint16, error)
+}
+
+// Hex32KindParser parses values as a "hex32".
+type Hex32KindParser interface {
+    Hex32E(value interface{}) (uint32, error)
+}
+
+// Hex64KindParser parses values as a "hex64".
+type Hex64KindParser interface {
+    Hex64E(value interface{}) (uint64, error)
+}
+
+// Float32KindParser parses values as a "float32".
+type Float32KindParser interface {
+    Float32E(value interface{}) (float32, error)
+}
+
+// Float64KindParser parses values as a "float64".
+type Float64KindParser interface {
+    Float64E(value interface{}) (float64, error)
+}
+
+// BoolKindParser parses values as a "bool".
+type BoolKindParser interface {
+    BoolE(value interface{}) (bool, error)
+}
+
+// Rfc3339KindParser parses values as an "rfc3339" timestamp.
+type Rfc3339KindParser interface {
+    Rfc3339E(value interface{}) (time.Time, error)
+}
+
+var (
+    // methodCapabilities find the conversion for a built-in kind in a parser,
+    // by the method name in NameMethodMap.
+    methodCapabilities = map[string]func(p interface{}) (ConvertFunc, bool) {
+        "String": func(p interface{}) (ConvertFunc, bool) {
+            return capability(p, StringKindParser.StringE, Parser.String)
+        },
+        "Int8": func(p interface{}) (ConvertFunc, bool) {
+            return capability(p, Int8KindParser.Int8E, Parser.Int8)
+        },
+        "Int16": func(p interface{}) (ConvertFunc, bool) {
+            return capability(p, Int16KindParser.Int16E, Parser.Int16)
+        },
+        "Int32": func(p interface{}) (ConvertFunc, bool) {
+            return capability(p, Int32KindParser.Int32E, Parser.Int32)
+        },
+        "Int64": func(p interface{}) (ConvertFunc, bool) {
+            return capability(p, Int64KindParser.Int64E, Parser.Int64)
+        },
+        "Uint8": func(p interface{}) (ConvertFunc, bool) {
+            return capability(p, Uint8KindParser.Uint8E, Parser.Uint8)
+        },
+        "Uint16": func(p interface{}) (ConvertFunc, bool) {
+            return capability(p, Uint16KindParser.Uint16E, Parser.Uint16)
+        },
+        "Uint32": func(p interface{}) (ConvertFunc, bool) {
+            return capability(p, Uint32KindParser.Uint32E, Parser.Uint32)
+        },
+        "Uint64": func(p interface{}) (ConvertFunc, bool) {
+            return capability(p, Uint64KindParser.Uint64E, Parser.Uint64)
+        },
+        "Hex8": func(p interface{}) (ConvertFunc, bool) {
+            return capability(p, Hex8KindParser.Hex8E, Parser.Hex8)
+        },
+        "Hex16": func(p interface{}) (ConvertFunc, bool) {
+            return capability(p, Hex16KindParser.Hex16E, Parser.Hex16)
+        },
+        "Hex32": func(p interface{}) (ConvertFunc, bool) {
+            return capability(p, Hex32KindParser.Hex32E, Parser.Hex32)
+        },
+        "Hex64": func(p interface{}) (ConvertFunc, bool) {
+            return capability(p, Hex64KindParser.Hex64E, Parser.Hex64)
+        },
+        "Float32": func(p interface{}) (ConvertFunc, bool) {
+            return capability(p, Float32KindParser.Float32E, Parser.Float32)
+        },
+        "Float64": func(p interface{}) (ConvertFunc, bool) {
+            return capability(p, Float64KindParser.Float64E, Parser.Float64)
+        },
+        "Bool": func(p interface{}) (ConvertFunc, bool) {
+            return capability(p, BoolKindParser.BoolE, Parser.Bool)
+        },
+        "Rfc3339": func(p interface{}) (ConvertFunc, bool) {
+            return capability(p, Rfc3339KindParser.Rfc3339E, Parser.Rfc3339)
+        },
+    }
+)
+
+// capability returns a conversion for a parser that implements the
+// capability interface P. Otherwise, the panicking method of a full Parser
+// is used, if the parser is one.
+func capability[P any, T any](p interface{}, methodE func(P, interface{}) (T, error), method func(Parser, interface{}) T) (convert ConvertFunc, found bool) {
+    if kp, ok := p.(P); ok == true {
+        convert = func(value interface{}) (interface{}, error) {
+            return methodE(kp, value)
+        }
+
+        return convert, true
+    } else if lp, ok := p.(Parser); ok == true {
+        convert = func(value interface{}) (interface{}, error) {
+            return method(lp, value), nil
+        }
+
+        return convert, true
+    }
+
+    return nil, false
+}
+
+// parserEntry is a registered parser along with its conversion for each
+// built-in kind that it supports. These are found once, when the parser is
+// registered, so that parsing does not have to inspect the parser.
+type parserEntry struct {
+    p interface{}
+
+    // converts are keyed by kind name.
+    converts map[string]ConvertFunc
+}
+
+// newParserEntry finds the conversions that the parser supports. It panics
+// if the parser does not support any kind.
+func newParserEntry(p interface{}) *parserEntry {
+    pe := &parserEntry{
+        p: p,
+        converts: make(map[string]ConvertFunc),
+    }
+
+    for kindName, mn := range NameMethodMap {
+        if convert, found := methodCapabilities[mn](p); found == true {
+            pe.converts[kindName] = convert
+        }
+    }
+
+    if len(pe.converts) == 0 {
+        log.Panic(fmt.Errorf("parser [%T] does not support any kind", p))
+    }
+
+    return pe
+}
+
+// Capability is an (input type, kind) pair that a registry can parse.
+type Capability struct {
+    // FromType is nil for kinds that accept any input (see AddKind() and
+    // AddTextKind()).
+    FromType reflect.Type
+
+    KindName string
+}
+
+// Capabilities lists the (input type, kind) pairs that are supported by the
+// registered parsers and conversions, sorted by type and then kind. Types
+// that are only matched indirectly (e.g. through a pointer or as a
+// fmt.Stringer) are not listed.
+func (r *Registry) Capabilities() []Capability {
+    r.lock.RLock()
+    defer r.lock.RUnlock()
+
+    found := make(map[Capability]bool)
+
+    for fromType, pe := range r.parsers {
+        for kindName := range pe.converts {
+            found[Capability{ FromType: fromType, KindName: kindName }] = true
+        }
+    }
+
+    for ck := range r.conversions {
+        found[Capability{ FromType: ck.fromType, KindName: ck.kindName }] = true
+    }
+
+    for kindName, kd := range r.kinds {
+        if kd.convert != nil || kd.textType != nil {
+            found[Capability{ KindName: kindName }] = true
+        }
+    }
+
+    capabilities := make([]Capability, 0, len(found))
+    for c := range found {
+        capabilities = append(capabilities, c)
+    }
+
+    typeName := func(t reflect.Type) string {
+        if t == nil {
+            return ""
+        }
+
+        return t.String()
+    }
+
+    sort.Slice(capabilities, func(i, j int) bool {
+        ti := typeName(capabilities[i].FromType)
+        tj := typeName(capabilities[j].FromType)
+
+        if ti != tj {
+            return ti < tj
+        }
+
+        return capabilities[i].KindName < capabilities[j].KindName
+    })
+
+    return capabilities
+}
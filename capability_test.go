@@ -0,0 +1,162 @@
+package parse
+
+import (
+    "testing"
+    "errors"
+    "reflect"
+    "strings"
+
+    "encoding/json"
+)
+
+// testCounter is parsed by testCounterParser.
+type testCounter struct {
+    count int64
+}
+
+// testCounterParser only supports the "int64" kind.
+type testCounterParser struct {
+}
+
+func (tcp testCounterParser) Int64E(value interface{}) (int64, error) {
+    return value.(testCounter).count, nil
+}
+
+func TestRegistry_AddParser_Partial(t *testing.T) {
+    r := NewRegistry()
+    r.AddParser(reflect.TypeOf(testCounter{}), testCounterParser{})
+
+    value, err := r.ParseE(testCounter{ count: 5 }, "int64")
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if value.(int64) != 5 {
+        t.Fatalf("Value not correct: [%v]", value)
+    }
+
+    _, err = r.ParseE(testCounter{ count: 5 }, "bool")
+    if errors.Is(err, ErrUnsupportedKind) == false {
+        t.Fatalf("Expected unsupported-kind error: [%v]", err)
+    } else if err.Error() != "parser [parse.testCounterParser] does not support kind [bool]" {
+        t.Fatalf("Error message not correct: [%s]", err)
+    }
+}
+
+func TestRegistry_AddParser_NoKinds(t *testing.T) {
+    defer func() {
+        errRaw := recover()
+        if errRaw == nil {
+            t.Fatalf("Expected panic for parser without any kinds.")
+        } else if strings.Contains(errRaw.(error).Error(), "does not support any kind") == false {
+            t.Fatalf("Panic not correct: [%v]", errRaw)
+        }
+    }()
+
+    NewRegistry().AddParser(reflect.TypeOf(testCounter{}), struct{}{})
+}
+
+func TestRegistry_GetParser_Partial(t *testing.T) {
+    r := NewRegistry()
+    r.AddParser(reflect.TypeOf(testCounter{}), testCounterParser{})
+
+    defer func() {
+        if errRaw := recover(); errRaw == nil {
+            t.Fatalf("Expected panic for a parser that does not implement every kind.")
+        }
+    }()
+
+    r.GetParser(reflect.TypeOf(testCounter{}))
+}
+
+func TestRegistry_Capabilities(t *testing.T) {
+    r := NewRegistry()
+    r.AddParser(reflect.TypeOf(testCounter{}), testCounterParser{})
+    r.AddKind("cents", reflect.TypeOf(testCents(0)), testCentsConvert)
+
+    found := make(map[Capability]bool)
+    for _, c := range r.Capabilities() {
+        found[c] = true
+    }
+
+    expected := []Capability {
+        { FromType: reflect.TypeOf(testCounter{}), KindName: "int64" },
+        { FromType: reflect.TypeOf(""), KindName: "uint64" },
+        { FromType: reflect.TypeOf(""), KindName: "rfc3339" },
+        { FromType: nil, KindName: "cents" },
+    }
+
+    for _, c := range expected {
+        if found[c] == false {
+            t.Fatalf("Capability not listed: %v", c)
+        }
+    }
+
+    if found[Capability{ FromType: reflect.TypeOf(testCounter{}), KindName: "bool" }] == true {
+        t.Fatalf("Unsupported kind listed.")
+    }
+}
+
+func TestParserE_Builtin(t *testing.T) {
+    parsers := []interface{} {
+        NewStringParser(),
+        NewTextParser(bytesString),
+    }
+
+    for _, p := range parsers {
+        if _, ok := p.(ParserE); ok == false {
+            t.Fatalf("Parser does not implement ParserE: [%T]", p)
+        }
+    }
+
+    // These only implement the kinds that they support.
+    partial := []interface{} {
+        NewNumericParser(),
+        NewBoolParser(),
+        NewTimeParser(),
+        NewJsonNumberParser(),
+    }
+
+    for _, p := range partial {
+        if _, ok := p.(Parser); ok == true {
+            t.Fatalf("Parser should not implement every kind: [%T]", p)
+        }
+    }
+}
+
+func TestRegistry_Capabilities_Builtin(t *testing.T) {
+    found := make(map[Capability]bool)
+    for _, c := range NewRegistry().Capabilities() {
+        found[c] = true
+    }
+
+    boolType := reflect.TypeOf(false)
+
+    supported := []Capability {
+        { FromType: timeType, KindName: "string" },
+        { FromType: timeType, KindName: "int64" },
+        { FromType: timeType, KindName: "rfc3339" },
+        { FromType: boolType, KindName: "bool" },
+        { FromType: boolType, KindName: "uint8" },
+    }
+
+    for _, c := range supported {
+        if found[c] == false {
+            t.Fatalf("Capability not listed: %v", c)
+        }
+    }
+
+    unsupported := []Capability {
+        { FromType: timeType, KindName: "int8" },
+        { FromType: timeType, KindName: "uint64" },
+        { FromType: timeType, KindName: "float64" },
+        { FromType: timeType, KindName: "bool" },
+        { FromType: boolType, KindName: "rfc3339" },
+        { FromType: reflect.TypeOf(float64(0)), KindName: "rfc3339" },
+        { FromType: reflect.TypeOf(json.Number("")), KindName: "rfc3339" },
+    }
+
+    for _, c := range unsupported {
+        if found[c] == true {
+            t.Fatalf("Unsupported capability listed: %v", c)
+        }
+    }
+}
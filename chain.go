@@ -0,0 +1,115 @@
+package parse
+
+import (
+    "flag"
+)
+
+// ChainStep is one of the sources of a ChainSource.
+type ChainStep struct {
+    Source Source
+
+    // Key returns the name to look up in this source for the name given to
+    // the chain (e.g. "APP_DB_HOST" for "db-host"). The name is used as-is
+    // if this is nil.
+    Key func(name string) string
+}
+
+// ChainSource looks values up in several sources in order and uses the first
+// one that has the name. Use WithOrigin() to find out which one supplied the
+// value. A value that is present but empty still ends the search (see
+// EmptyPolicy).
+type ChainSource struct {
+    steps []ChainStep
+}
+
+// NewChainSource returns a Source that tries each step in order (e.g. a
+// flag, then the environment, then a configuration file).
+func NewChainSource(steps ...ChainStep) Source {
+    return &ChainSource{
+        steps: steps,
+    }
+}
+
+func (cs *ChainSource) lookupOrigin(name string) (valueRaw interface{}, present bool, origin Origin) {
+    for _, step := range cs.steps {
+        key := name
+        if step.Key != nil {
+            key = step.Key(name)
+        }
+
+        valueRaw, present, origin = lookupOrigin(step.Source, key)
+        if present == true {
+            return valueRaw, true, origin
+        }
+    }
+
+    origin = Origin{
+        Source: SourceChain,
+        Name: name,
+    }
+
+    return nil, false, origin
+}
+
+func (cs *ChainSource) Lookup(name string) (valueRaw interface{}, present bool) {
+    valueRaw, present, _ = cs.lookupOrigin(name)
+    return valueRaw, present
+}
+
+func (cs *ChainSource) Describe() string {
+    return SourceChain
+}
+
+// FlagSource looks up the flags that were set on the command-line.
+type FlagSource struct {
+    fs *flag.FlagSet
+}
+
+// NewFlagSource returns a Source for the flags that were set explicitly
+// (after fs.Parse()). Flags that were not set are absent, even if they have
+// a default, so that a ChainSource may fall back to another source. Pass
+// flag.CommandLine for the standard flags.
+func NewFlagSource(fs *flag.FlagSet) Source {
+    return &FlagSource{
+        fs: fs,
+    }
+}
+
+func (fls *FlagSource) Lookup(name string) (valueRaw interface{}, present bool) {
+    fls.fs.Visit(func(f *flag.Flag) {
+        if f.Name == name {
+            valueRaw = f.Value.String()
+            present = true
+        }
+    })
+
+    return valueRaw, present
+}
+
+func (fls *FlagSource) Describe() string {
+    return SourceFlag
+}
+
+// FuncSource looks values up with a function.
+type FuncSource struct {
+    description string
+    lookup func(name string) (valueRaw interface{}, present bool)
+}
+
+// NewFuncSource returns a Source that looks values up with the given
+// function (e.g. a router's path parameters). The description is reported
+// by ParseError.Source().
+func NewFuncSource(description string, lookup func(name string) (valueRaw interface{}, present bool)) Source {
+    return &FuncSource{
+        description: description,
+        lookup: lookup,
+    }
+}
+
+func (fs *FuncSource) Lookup(name string) (valueRaw interface{}, present bool) {
+    return fs.lookup(name)
+}
+
+func (fs *FuncSource) Describe() string {
+    return fs.description
+}
@@ -0,0 +1,184 @@
+package parse
+
+import (
+    "testing"
+    "errors"
+    "strings"
+
+    "flag"
+)
+
+func testConfigChain(t *testing.T, args []string) Source {
+    fs := flag.NewFlagSet("test", flag.ContinueOnError)
+    fs.String("db-host", "flag-default", "")
+    fs.Int("db-port", 0, "")
+
+    if err := fs.Parse(args); err != nil {
+        t.Fatalf("Could not parse flags: [%s]", err)
+    }
+
+    config := map[string]interface{} {
+        "db": map[string]interface{} {
+            "host": "file-host",
+            "port": "5432",
+        },
+    }
+
+    return NewChainSource(
+        ChainStep{
+            Source: NewFlagSource(fs),
+        },
+        ChainStep{
+            Source: NewEnvironSource(),
+            Key: func(name string) string {
+                return "MULTIPARSE_TEST_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+            },
+        },
+        ChainStep{
+            Source: NewInterfaceMapSource(config),
+            Key: func(name string) string {
+                return strings.ReplaceAll(name, "-", ".")
+            },
+        })
+}
+
+func TestChainSource(t *testing.T) {
+    t.Setenv("MULTIPARSE_TEST_DB_HOST", "env-host")
+
+    cs := testConfigChain(t, []string { "--db-port", "6543" })
+
+    origin := Origin{}
+
+    if value := Get(cs, "db-port", "uint16", true, WithOrigin(&origin)); value != uint16(6543) {
+        t.Fatalf("Flag value not correct: [%v]", value)
+    } else if origin.Source != SourceFlag || origin.Name != "db-port" {
+        t.Fatalf("Flag origin not correct: %v", origin)
+    }
+
+    if value := Get(cs, "db-host", "string", true, WithOrigin(&origin)); value != "env-host" {
+        t.Fatalf("Environment value not correct: [%v]", value)
+    } else if origin.Source != SourceEnviron || origin.Name != "MULTIPARSE_TEST_DB_HOST" {
+        t.Fatalf("Environment origin not correct: %v", origin)
+    }
+
+    cs = testConfigChain(t, nil)
+    t.Setenv("MULTIPARSE_TEST_DB_HOST", "")
+    t.Setenv("MULTIPARSE_TEST_DB_PORT", "abc")
+
+    // The environment variable is set but empty, so it is used and is then
+    // treated as missing.
+    if value := Get(cs, "db-host", "string", false, WithOrigin(&origin)); value != nil {
+        t.Fatalf("Expected empty value to be absent: [%v]", value)
+    } else if origin.Source != "" {
+        t.Fatalf("Expected no origin for absent value: %v", origin)
+    }
+
+    // Errors are reported against the source that supplied the value.
+    _, err := GetE(cs, "db-port", "uint16", true)
+
+    var pe *ParseError
+    if errors.As(err, &pe) == false {
+        t.Fatalf("Expected parse error: [%v]", err)
+    } else if pe.Source() != SourceEnviron || pe.Name() != "MULTIPARSE_TEST_DB_PORT" {
+        t.Fatalf("Error not annotated correctly: [%s] [%s]", pe.Source(), pe.Name())
+    }
+}
+
+func TestChainSource_Fallback(t *testing.T) {
+    cs := testConfigChain(t, nil)
+
+    origin := Origin{}
+
+    if value := Get(cs, "db-host", "string", true, WithOrigin(&origin)); value != "file-host" {
+        t.Fatalf("File value not correct: [%v]", value)
+    } else if origin.Source != SourceMap || origin.Name != "db.host" {
+        t.Fatalf("File origin not correct: %v", origin)
+    }
+
+    limitDefault := WithDefault("uint32", 50)
+
+    if value := Get(cs, "limit", "uint32", false, limitDefault, WithOrigin(&origin)); value != uint32(50) {
+        t.Fatalf("Default not correct: [%v]", value)
+    } else if origin.Source != SourceDefault || origin.Name != "limit" {
+        t.Fatalf("Default origin not correct: %v", origin)
+    }
+
+    _, err := GetE(cs, "limit", "uint32", true)
+    if errors.Is(err, ErrMissing) == false {
+        t.Fatalf("Expected missing error: [%v]", err)
+    } else if err.Error() != "value empty or omitted from every source: [limit]" {
+        t.Fatalf("Message not correct: [%s]", err)
+    }
+}
+
+func TestChainSource_Nested(t *testing.T) {
+    inner := NewChainSource(
+        ChainStep{ Source: NewMapSource(map[string]string { "a": "1" }) })
+
+    outer := NewChainSource(
+        ChainStep{ Source: NewMapSource(map[string]string {}) },
+        ChainStep{ Source: inner })
+
+    origin := Origin{}
+
+    if value := Get(outer, "a", "int8", true, WithOrigin(&origin)); value != int8(1) {
+        t.Fatalf("Value not correct: [%v]", value)
+    } else if origin.Source != SourceMap || origin.Name != "a" {
+        t.Fatalf("Origin not correct: %v", origin)
+    }
+}
+
+func TestFlagSource_Unset(t *testing.T) {
+    fs := flag.NewFlagSet("test", flag.ContinueOnError)
+    fs.String("name", "default", "")
+
+    if err := fs.Parse(nil); err != nil {
+        t.Fatalf("Could not parse flags: [%s]", err)
+    }
+
+    if _, present := NewFlagSource(fs).Lookup("name"); present == true {
+        t.Fatalf("Flag that was not set should be absent.")
+    }
+}
+
+func TestInterfaceMapSource_Path(t *testing.T) {
+    ims := NewInterfaceMapSource(map[string]interface{} {
+        "a.b": "literal",
+        "a": map[string]interface{} {
+            "c": map[string]interface{} {
+                "d": 4.0,
+            },
+            "e": "not-a-map",
+        },
+    })
+
+    if valueRaw, _ := ims.Lookup("a.b"); valueRaw != "literal" {
+        t.Fatalf("Literal key not preferred: [%v]", valueRaw)
+    } else if valueRaw, _ := ims.Lookup("a.c.d"); valueRaw != 4.0 {
+        t.Fatalf("Path not followed: [%v]", valueRaw)
+    } else if _, present := ims.Lookup("a.e.f"); present == true {
+        t.Fatalf("Path through non-map should be absent.")
+    } else if _, present := ims.Lookup("a.x"); present == true {
+        t.Fatalf("Absent path should be absent.")
+    }
+}
+
+func TestFuncSource(t *testing.T) {
+    params := map[string]string { "id": "42" }
+
+    ps := NewFuncSource("path", func(name string) (interface{}, bool) {
+        value, found := params[name]
+        return value, found
+    })
+
+    if value := Get(ps, "id", "uint64", true); value != uint64(42) {
+        t.Fatalf("Value not correct: [%v]", value)
+    }
+
+    _, err := GetE(ps, "other", "uint64", true)
+
+    var pe *ParseError
+    if errors.As(err, &pe) == false || pe.Source() != "path" {
+        t.Fatalf("Error source not correct: [%v]", err)
+    }
+}
@@ -1,21 +1,272 @@
 package parse
 
+import (
+    "errors"
+    "fmt"
+    "runtime"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Sources that a value can be read from. These are reported by
+// ParseError.Source().
+const (
+    SourceQuery = "query"
+    SourceBody = "body"
+    SourceHeader = "header"
+    SourceCookie = "cookie"
+    SourceJson = "json"
+    SourceMap = "map"
+    SourceEnviron = "env"
+    SourceFlag = "flag"
+    SourceChain = "chain"
+
+    // SourceDefault is recorded by WithOrigin() when the default was used.
+    SourceDefault = "default"
+)
+
+// Sentinel causes. Every *ParseError produced by this package is classified
+// by exactly one of these (see ParseError.Cause()), so parse failures may be
+// tested with errors.Is(). Setup errors (e.g. an invalid struct tag or
+// destination) are not classified.
+var (
+    // ErrMissing indicates that a required value was empty or omitted.
+    ErrMissing = errors.New("value missing")
+
+    // ErrSyntax indicates that a value could not be parsed as the requested
+    // kind.
+    ErrSyntax = errors.New("invalid syntax")
+
+    // ErrRange indicates that a value was well-formed but did not fit in the
+    // requested kind.
+    ErrRange = errors.New("value out of range")
+
+    // ErrUnsupportedKind indicates that the requested kind is not known or
+    // can not be produced from the type of the given value.
+    ErrUnsupportedKind = errors.New("unsupported kind")
+)
+
+// ParseError describes a failure to read or parse a single value.
 type ParseError struct {
+    field string
+    name string
+    source string
+    kindName string
     value interface{}
     message string
+    cause error
+    err error
+}
+
+// Field is the path of the struct field being bound (e.g. "Filter.Limit"),
+// if the error was produced while binding a struct.
+func (pe *ParseError) Field() string {
+    return pe.field
+}
+
+// Name is the name of the field or argument being parsed, if known.
+func (pe *ParseError) Name() string {
+    return pe.name
+}
+
+// Source is the source that the value was read from (e.g. SourceQuery), if
+// known.
+func (pe *ParseError) Source() string {
+    return pe.source
+}
+
+// KindName is the kind that was requested, if known.
+func (pe *ParseError) KindName() string {
+    return pe.kindName
 }
 
+// Value is the raw input value. This is nil if the value was missing.
 func (pe *ParseError) Value() interface{} {
     return pe.value
 }
 
+// Cause is the sentinel that classifies this error (e.g. ErrSyntax). This is
+// nil for errors built with NewParseError().
+func (pe *ParseError) Cause() error {
+    return pe.cause
+}
+
 func (pe *ParseError) Error() string {
     return pe.message
 }
 
+// Unwrap exposes both the sentinel cause and the underlying error (e.g. a
+// *strconv.NumError) to errors.Is() and errors.As().
+func (pe *ParseError) Unwrap() []error {
+    errs := make([]error, 0, 2)
+
+    if pe.cause != nil {
+        errs = append(errs, pe.cause)
+    }
+
+    if pe.err != nil {
+        errs = append(errs, pe.err)
+    }
+
+    return errs
+}
+
+// withField returns a copy of the error annotated with the source and name of
+// the value.
+func (pe *ParseError) withField(source, name string) *ParseError {
+    copied := *pe
+    copied.source = source
+    copied.name = name
+
+    return &copied
+}
+
+// withPath returns a copy of the error annotated with the path of the struct
+// field being bound.
+func (pe *ParseError) withPath(field string) *ParseError {
+    copied := *pe
+    copied.field = field
+
+    return &copied
+}
+
 func NewParseError(value interface{}, message string) error {
     return &ParseError{
         value: value,
         message: message,
     }
 }
+
+// newParseError builds a classified error. If `err` is given, it is wrapped
+// and its message is used when `message` is empty.
+func newParseError(kindName string, value interface{}, cause error, err error, message string) *ParseError {
+    if message == "" && err != nil {
+        message = err.Error()
+    }
+
+    return &ParseError{
+        kindName: kindName,
+        value: value,
+        message: message,
+        cause: cause,
+        err: err,
+    }
+}
+
+// newMissingError builds the error for a required value that is absent.
+func newMissingError(source, name, kindName, message string) *ParseError {
+    pe := newParseError(kindName, nil, ErrMissing, nil, message)
+    return pe.withField(source, name)
+}
+
+// annotateError attaches the source and name to a *ParseError. Any other
+// error is returned unchanged.
+func annotateError(err error, source, name string) error {
+    if pe, ok := err.(*ParseError); ok == true {
+        return pe.withField(source, name)
+    }
+
+    return err
+}
+
+// classifyError determines the sentinel cause for an error returned by a
+// parser.
+func classifyError(err error) error {
+    for _, sentinel := range []error { ErrMissing, ErrSyntax, ErrRange, ErrUnsupportedKind } {
+        if errors.Is(err, sentinel) == true {
+            return sentinel
+        }
+    }
+
+    if errors.Is(err, strconv.ErrRange) == true {
+        return ErrRange
+    }
+
+    var tpe *time.ParseError
+    if errors.As(err, &tpe) == true {
+        // Out-of-range fields (e.g. month 13) say so in the message. Other
+        // messages (e.g. "extra text") are syntax errors.
+        if strings.Contains(tpe.Message, "out of range") == true {
+            return ErrRange
+        }
+    }
+
+    return ErrSyntax
+}
+
+// IsClientError indicates whether the error was caused by bad input (a
+// missing, malformed, or out-of-range value) rather than by a programming
+// error such as an unknown kind. A ParseErrors is a client error only if all
+// of its errors are.
+func IsClientError(err error) bool {
+    var pes ParseErrors
+    if errors.As(err, &pes) == true {
+        if len(pes) == 0 {
+            return false
+        }
+
+        for _, pe := range pes {
+            if isClientCause(pe.cause) == false {
+                return false
+            }
+        }
+
+        return true
+    }
+
+    var pe *ParseError
+    if errors.As(err, &pe) == true {
+        return isClientCause(pe.cause)
+    }
+
+    return false
+}
+
+func isClientCause(cause error) bool {
+    return cause == ErrMissing || cause == ErrSyntax || cause == ErrRange
+}
+
+// recoveredError converts a recovered panic value to an error. A runtime
+// error (e.g. a write to a nil map) is a bug rather than bad input, so it is
+// re-raised instead.
+func recoveredError(errRaw interface{}) error {
+    if err, ok := errRaw.(error); ok == true {
+        if _, ok := unwrapPanicError(err).(runtime.Error); ok == true {
+            panic(errRaw)
+        }
+    }
+
+    if err, ok := errRaw.(error); ok == true {
+        return err
+    }
+
+    return fmt.Errorf("%v", errRaw)
+}
+
+// ParseErrors is an ordered collection of parse failures, as returned when
+// every failure is collected rather than only the first.
+type ParseErrors []*ParseError
+
+func (pes ParseErrors) Error() string {
+    messages := make([]string, len(pes))
+    for i, pe := range pes {
+        if pe.field != "" {
+            messages[i] = fmt.Sprintf("%s: %s", pe.field, pe.message)
+        } else {
+            messages[i] = pe.message
+        }
+    }
+
+    return fmt.Sprintf("%d value(s) not valid: %s", len(pes), strings.Join(messages, "; "))
+}
+
+// Unwrap exposes each error to errors.Is() and errors.As().
+func (pes ParseErrors) Unwrap() []error {
+    errs := make([]error, len(pes))
+    for i, pe := range pes {
+        errs[i] = pe
+    }
+
+    return errs
+}
@@ -0,0 +1,136 @@
+package parse
+
+import (
+    "testing"
+    "errors"
+    "strconv"
+
+    "net/http"
+)
+
+func TestParseError_Syntax(t *testing.T) {
+    _, err := ParseE("abc", "int64")
+    if errors.Is(err, ErrSyntax) == false {
+        t.Fatalf("Error is not a syntax error: [%v]", err)
+    }
+
+    var ne *strconv.NumError
+    if errors.As(err, &ne) == false {
+        t.Fatalf("Underlying error not exposed: [%v]", err)
+    }
+
+    var pe *ParseError
+    if errors.As(err, &pe) == false {
+        t.Fatalf("Error is not a ParseError: [%v]", err)
+    } else if pe.KindName() != "int64" {
+        t.Fatalf("Kind-name not correct: [%s]", pe.KindName())
+    } else if pe.Value() != "abc" {
+        t.Fatalf("Value not correct: [%v]", pe.Value())
+    } else if pe.Cause() != ErrSyntax {
+        t.Fatalf("Cause not correct: [%v]", pe.Cause())
+    }
+}
+
+func TestParseError_Range(t *testing.T) {
+    _, err := ParseE("300", "uint8")
+    if errors.Is(err, ErrRange) == false {
+        t.Fatalf("Error is not a range error: [%v]", err)
+    }
+}
+
+func TestParseError_Time(t *testing.T) {
+    _, err := ParseE("2016-13-08T19:32:29Z", "rfc3339")
+    if errors.Is(err, ErrRange) == false {
+        t.Fatalf("Out-of-range month is not a range error: [%v]", err)
+    }
+
+    _, err = ParseE("2016-11-08T19:32:29Zjunk", "rfc3339")
+    if errors.Is(err, ErrSyntax) == false {
+        t.Fatalf("Trailing text is not a syntax error: [%v]", err)
+    }
+}
+
+func TestParseError_UnsupportedKind(t *testing.T) {
+    _, err := ParseE("123", "invalid-kind")
+    if errors.Is(err, ErrUnsupportedKind) == false {
+        t.Fatalf("Error is not an unsupported-kind error: [%v]", err)
+    }
+
+    _, err = ParseE(struct{}{}, "uint8")
+    if errors.Is(err, ErrUnsupportedKind) == false {
+        t.Fatalf("Error is not an unsupported-kind error: [%v]", err)
+    }
+}
+
+func TestParseError_Missing(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    _, err = FromRequestHeaderE(req, "X-Id", "uint64", true)
+    if errors.Is(err, ErrMissing) == false {
+        t.Fatalf("Error is not a missing error: [%v]", err)
+    }
+
+    pe := err.(*ParseError)
+    if pe.Name() != "X-Id" {
+        t.Fatalf("Name not correct: [%s]", pe.Name())
+    } else if pe.Source() != SourceHeader {
+        t.Fatalf("Source not correct: [%s]", pe.Source())
+    } else if pe.KindName() != "uint64" {
+        t.Fatalf("Kind-name not correct: [%s]", pe.KindName())
+    }
+}
+
+func TestParseError_Field(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com?id=abc", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    _, err = FromRequestQueryE(req, "id", "uint64", true)
+
+    pe := err.(*ParseError)
+    if pe.Name() != "id" {
+        t.Fatalf("Name not correct: [%s]", pe.Name())
+    } else if pe.Source() != SourceQuery {
+        t.Fatalf("Source not correct: [%s]", pe.Source())
+    } else if pe.Value() != "abc" {
+        t.Fatalf("Value not correct: [%v]", pe.Value())
+    } else if errors.Is(err, ErrSyntax) == false {
+        t.Fatalf("Error is not a syntax error: [%v]", err)
+    }
+}
+
+func TestParseErrors_Error(t *testing.T) {
+    pes := ParseErrors{
+        newMissingError(SourceQuery, "aa", "uint64", "query argument empty or omitted: [aa]").withPath("Aa"),
+        newParseError("uint64", "x", ErrSyntax, nil, "invalid value"),
+    }
+
+    expected := "2 value(s) not valid: Aa: query argument empty or omitted: [aa]; invalid value"
+    if pes.Error() != expected {
+        t.Fatalf("Message not correct: [%s]", pes.Error())
+    }
+}
+
+func TestIsClientError(t *testing.T) {
+    _, err := ParseE("abc", "uint64")
+    if IsClientError(err) != true {
+        t.Fatalf("Syntax error should be a client error.")
+    }
+
+    _, err = ParseE("123", "invalid-kind")
+    if IsClientError(err) != false {
+        t.Fatalf("Unsupported kind should not be a client error.")
+    }
+
+    if IsClientError(NewParseError(nil, "some error")) != false {
+        t.Fatalf("Unclassified error should not be a client error.")
+    }
+
+    if IsClientError(errors.New("some error")) != false {
+        t.Fatalf("Other error should not be a client error.")
+    }
+}
@@ -0,0 +1,250 @@
+package parse
+
+import (
+    "fmt"
+    "reflect"
+
+    "flag"
+    "encoding"
+
+    "github.com/dsoprea/go-logging"
+)
+
+var (
+    textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+    flagValueType = reflect.TypeOf((*flag.Value)(nil)).Elem()
+)
+
+// isTextType indicates whether values of the given type can be populated
+// from a string because a pointer to the type (or to the type that it points
+// to) implements encoding.TextUnmarshaler or flag.Value. time.Time is
+// excluded since it has its own kind ("rfc3339").
+func isTextType(t reflect.Type) bool {
+    if t.Kind() == reflect.Ptr {
+        t = t.Elem()
+    }
+
+    if t == timeType {
+        return false
+    }
+
+    pt := reflect.PtrTo(t)
+    return pt.Implements(textUnmarshalerType) == true || pt.Implements(flagValueType) == true
+}
+
+// unmarshalText populates a new value of the given type from a string. See
+// isTextType().
+func unmarshalText(t reflect.Type, s string) (value interface{}, err error) {
+    isPtr := t.Kind() == reflect.Ptr
+
+    et := t
+    if isPtr == true {
+        et = t.Elem()
+    }
+
+    pv := reflect.New(et)
+
+    switch target := pv.Interface().(type) {
+    case encoding.TextUnmarshaler:
+        err = target.UnmarshalText([]byte(s))
+    case flag.Value:
+        err = target.Set(s)
+    default:
+        return nil, fmt.Errorf("%w: type [%s] does not implement encoding.TextUnmarshaler or flag.Value", ErrUnsupportedKind, t)
+    }
+
+    if err != nil {
+        return nil, err
+    }
+
+    if isPtr == true {
+        return pv.Interface(), nil
+    }
+
+    return pv.Elem().Interface(), nil
+}
+
+// ConvertFunc converts a raw value to a kind. It returns an error (which
+// may wrap ErrSyntax or ErrRange) if the value is not valid for the kind.
+type ConvertFunc func(valueRaw interface{}) (value interface{}, err error)
+
+// kindDef describes a kind that values may be parsed to.
+type kindDef struct {
+    // zeroType is the type of the values that the kind produces.
+    zeroType reflect.Type
+
+    // textType is set for kinds registered with AddTextKind().
+    textType reflect.Type
+
+    // convert is the conversion used when there is none for the specific
+    // input type (see AddConversion()).
+    convert ConvertFunc
+}
+
+// conversionKey identifies a conversion from an input type to a kind.
+type conversionKey struct {
+    fromType reflect.Type
+    kindName string
+}
+
+// builtinKinds returns the kinds described by NameMethodMap and
+// KindNameZeroType. Their values are parsed by the parser registered for the
+// input type.
+func builtinKinds() map[string]kindDef {
+    kinds := make(map[string]kindDef, len(NameMethodMap))
+    for kindName := range NameMethodMap {
+        kinds[kindName] = kindDef{
+            zeroType: KindNameZeroType[kindName],
+        }
+    }
+
+    return kinds
+}
+
+// AddKind registers a kind with the default registry. See
+// Registry.AddKind().
+func AddKind(kindName string, zeroType reflect.Type, convert ConvertFunc) {
+    DefaultRegistry.AddKind(kindName, zeroType, convert)
+}
+
+// AddKind registers a kind whose values have the given type. The conversion
+// is used for every input type that does not have its own conversion (see
+// AddConversion()). The kind may then be used anywhere a kind name is
+// accepted. It panics if the name is already used by a built-in kind or if
+// the registry is frozen.
+func (r *Registry) AddKind(kindName string, zeroType reflect.Type, convert ConvertFunc) {
+    if zeroType == nil {
+        log.Panic(fmt.Errorf("kind [%s] has no zero-type", kindName))
+    } else if convert == nil {
+        log.Panic(fmt.Errorf("kind [%s] has no conversion", kindName))
+    }
+
+    r.addKind(kindName, kindDef{ zeroType: zeroType, convert: convert })
+}
+
+func (r *Registry) addKind(kindName string, kd kindDef) {
+    if _, found := NameMethodMap[kindName]; found == true {
+        log.Panic(fmt.Errorf("kind [%s] is a built-in kind", kindName))
+    }
+
+    r.lock.Lock()
+    defer r.lock.Unlock()
+
+    if r.frozen == true {
+        log.Panic(ErrRegistryFrozen)
+    }
+
+    r.kinds[kindName] = kd
+}
+
+// AddConversion registers a conversion from values of exactly the given type
+// to a kind with the default registry. See Registry.AddConversion().
+func AddConversion(fromType reflect.Type, kindName string, convert ConvertFunc) {
+    DefaultRegistry.AddConversion(fromType, kindName, convert)
+}
+
+// AddConversion registers a conversion from values of exactly the given type
+// to a kind. It takes precedence over the kind's own conversion and, for the
+// built-in kinds, over the parser registered for the type. This allows a
+// single (input type, kind) pair to be supported without implementing every
+// Parser method. It panics if the kind is not registered or if the registry
+// is frozen.
+func (r *Registry) AddConversion(fromType reflect.Type, kindName string, convert ConvertFunc) {
+    if convert == nil {
+        log.Panic(fmt.Errorf("conversion from type [%s] to kind [%s] is nil", fromType, kindName))
+    }
+
+    r.lock.Lock()
+    defer r.lock.Unlock()
+
+    if r.frozen == true {
+        log.Panic(ErrRegistryFrozen)
+    } else if _, found := r.kinds[kindName]; found == false {
+        log.Panic(fmt.Errorf("kind [%s] not valid", kindName))
+    }
+
+    ck := conversionKey{
+        fromType: fromType,
+        kindName: kindName,
+    }
+
+    r.conversions[ck] = convert
+}
+
+// AddTextKind registers a kind name for a type that can be populated from a
+// string with the default registry. See Registry.AddTextKind().
+func AddTextKind(kindName string, t reflect.Type) {
+    DefaultRegistry.AddTextKind(kindName, t)
+}
+
+// AddTextKind registers a kind name for a type whose pointer implements
+// encoding.TextUnmarshaler or flag.Value. The kind may then be used anywhere
+// a kind name is accepted (e.g. Parse() and FromRequestQuery()). Values are
+// parsed as a "string" and then unmarshaled into a new value of the type. It
+// panics if the type is not supported, if the name is already used by a
+// built-in kind, or if the registry is frozen.
+func (r *Registry) AddTextKind(kindName string, t reflect.Type) {
+    if isTextType(t) == false {
+        log.Panic(fmt.Errorf("type [%s] does not implement encoding.TextUnmarshaler or flag.Value", t))
+    }
+
+    r.addKind(kindName, kindDef{ zeroType: t, textType: t })
+}
+
+// kind returns the definition of the given kind.
+func (r *Registry) kind(kindName string) (kd kindDef, found bool) {
+    r.lock.RLock()
+    defer r.lock.RUnlock()
+
+    kd, found = r.kinds[kindName]
+    return kd, found
+}
+
+// kindType returns the type that values of the given kind have.
+func (r *Registry) kindType(kindName string) (t reflect.Type, found bool) {
+    kd, found := r.kind(kindName)
+    return kd.zeroType, found
+}
+
+// ParseType parses the value to the given type using the default registry.
+// See Registry.ParseType().
+func ParseType(valueRaw interface{}, t reflect.Type) interface{} {
+    return DefaultRegistry.ParseType(valueRaw, t)
+}
+
+// ParseTypeE is the error-returning variant of ParseType.
+func ParseTypeE(valueRaw interface{}, t reflect.Type) (value interface{}, err error) {
+    return DefaultRegistry.ParseTypeE(valueRaw, t)
+}
+
+// ParseType parses the value to the given type. Types that can be populated
+// from a string are unmarshaled (see AddTextKind()). Otherwise, the kind is
+// inferred from the type (e.g. "uint64" for uint64, or any named type based
+// on it). It panics on failure.
+func (r *Registry) ParseType(valueRaw interface{}, t reflect.Type) interface{} {
+    value, err := r.ParseTypeE(valueRaw, t)
+    log.PanicIf(err)
+
+    return value
+}
+
+// ParseTypeE is the error-returning variant of ParseType. A nil value
+// produces the zero-value of the type.
+func (r *Registry) ParseTypeE(valueRaw interface{}, t reflect.Type) (value interface{}, err error) {
+    if valueRaw == nil {
+        return reflect.Zero(t).Interface(), nil
+    }
+
+    if isTextType(t) == true {
+        return r.parseText(valueRaw, "", t)
+    } else if kindName, found := kindNameForType(t); found == true {
+        value, err = r.ParseE(valueRaw, kindName)
+        if err != nil {
+            return nil, err
+        }
+
+        return reflect.ValueOf(value).Convert(t).Interface(), nil
+    }
+
+    return nil, newParseError("", valueRaw, ErrUnsupportedKind, nil, fmt.Sprintf("type [%s] is not supported", t))
+}
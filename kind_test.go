@@ -0,0 +1,428 @@
+package parse
+
+import (
+    "testing"
+    "errors"
+    "fmt"
+    "reflect"
+    "strings"
+
+    "net"
+    "net/http"
+)
+
+// testLevel is populated through flag.Value.
+type testLevel int
+
+func (tl *testLevel) String() string {
+    return fmt.Sprintf("%d", int(*tl))
+}
+
+func (tl *testLevel) Set(s string) error {
+    switch s {
+    case "low":
+        *tl = 1
+    case "high":
+        *tl = 2
+    default:
+        return fmt.Errorf("%w: level [%s] not valid", ErrSyntax, s)
+    }
+
+    return nil
+}
+
+func TestRegistry_AddTextKind(t *testing.T) {
+    r := NewRegistry()
+    r.AddTextKind("ip", reflect.TypeOf(net.IP{}))
+
+    value, err := r.ParseE("127.0.0.1", "ip")
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    }
+
+    ip := value.(net.IP)
+    if ip.Equal(net.IPv4(127, 0, 0, 1)) == false {
+        t.Fatalf("Value not correct: [%s]", ip)
+    }
+
+    _, err = r.ParseE("not-an-ip", "ip")
+    if errors.Is(err, ErrSyntax) == false || IsClientError(err) == false {
+        t.Fatalf("Expected syntax error: [%v]", err)
+    } else if err.(*ParseError).KindName() != "ip" {
+        t.Fatalf("Error kind not correct: [%s]", err.(*ParseError).KindName())
+    }
+
+    if _, err := NewRegistry().ParseE("127.0.0.1", "ip"); errors.Is(err, ErrUnsupportedKind) == false {
+        t.Fatalf("Kind leaked into another registry: [%v]", err)
+    }
+}
+
+func TestRegistry_AddTextKind_FlagValue(t *testing.T) {
+    r := NewRegistry()
+    r.AddTextKind("level", reflect.TypeOf(testLevel(0)))
+
+    value, err := r.ParseE([]byte("high"), "level")
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if value.(testLevel) != 2 {
+        t.Fatalf("Value not correct: [%v]", value)
+    }
+
+    _, err = r.ParseE("medium", "level")
+    if errors.Is(err, ErrSyntax) == false || IsClientError(err) == false {
+        t.Fatalf("Expected syntax error: [%v]", err)
+    }
+}
+
+func TestAddTextKind_Query(t *testing.T) {
+    AddTextKind("test-level", reflect.TypeOf(testLevel(0)))
+
+    req, err := http.NewRequest("GET", "http://example.com?level=low", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    if value := FromRequestQuery(req, "level", "test-level", true); value.(testLevel) != 1 {
+        t.Fatalf("Value not correct: [%v]", value)
+    }
+}
+
+func TestRegistry_AddTextKind_InvalidType(t *testing.T) {
+    defer func() {
+        if errRaw := recover(); errRaw == nil {
+            t.Fatalf("Expected panic for type that can not be unmarshaled.")
+        }
+    }()
+
+    NewRegistry().AddTextKind("number", reflect.TypeOf(0))
+}
+
+func TestRegistry_AddTextKind_BuiltinKind(t *testing.T) {
+    defer func() {
+        errRaw := recover()
+        if errRaw == nil {
+            t.Fatalf("Expected panic for built-in kind.")
+        } else if strings.Contains(errRaw.(error).Error(), "built-in") == false {
+            t.Fatalf("Panic not correct: [%v]", errRaw)
+        }
+    }()
+
+    NewRegistry().AddTextKind("uint64", reflect.TypeOf(net.IP{}))
+}
+
+func TestRegistry_AddTextKind_Frozen(t *testing.T) {
+    r := NewRegistry()
+    r.Freeze()
+
+    defer func() {
+        errRaw := recover()
+        if errRaw == nil {
+            t.Fatalf("Expected panic when registering with frozen registry.")
+        } else if errors.Is(unwrapPanicError(errRaw.(error)), ErrRegistryFrozen) == false {
+            t.Fatalf("Panic not correct: [%v]", errRaw)
+        }
+    }()
+
+    r.AddTextKind("ip", reflect.TypeOf(net.IP{}))
+}
+
+func TestParseTypeE(t *testing.T) {
+    value, err := ParseTypeE("high", reflect.TypeOf(testLevel(0)))
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if value.(testLevel) != 2 {
+        t.Fatalf("Value not correct: [%v]", value)
+    }
+
+    value, err = ParseTypeE("high", reflect.TypeOf((*testLevel)(nil)))
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if *value.(*testLevel) != 2 {
+        t.Fatalf("Pointer value not correct: [%v]", value)
+    }
+
+    value, err = ParseTypeE("123", reflect.TypeOf(testAccountId(0)))
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if value.(testAccountId) != 123 {
+        t.Fatalf("Named value not correct: [%v]", value)
+    }
+
+    value, err = ParseTypeE(nil, reflect.TypeOf(testLevel(0)))
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if value.(testLevel) != 0 {
+        t.Fatalf("Expected zero-value: [%v]", value)
+    }
+
+    _, err = ParseTypeE("abc", reflect.TypeOf(struct{}{}))
+    if errors.Is(err, ErrUnsupportedKind) == false {
+        t.Fatalf("Expected unsupported-kind error: [%v]", err)
+    }
+}
+
+func TestBindE_TextType(t *testing.T) {
+    type request struct {
+        Address net.IP `multiparse:"query=address,required"`
+        Level *testLevel `multiparse:"header=X-Level"`
+    }
+
+    req, err := http.NewRequest("GET", "http://example.com?address=10.0.0.1", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    req.Header.Set("X-Level", "low")
+
+    tr := request{}
+
+    err = BindE(req, &tr)
+    if err != nil {
+        t.Fatalf("Bind failed: [%s]", err)
+    } else if tr.Address.Equal(net.IPv4(10, 0, 0, 1)) == false {
+        t.Fatalf("Address not correct: [%s]", tr.Address)
+    } else if tr.Level == nil || *tr.Level != 1 {
+        t.Fatalf("Level not correct: [%v]", tr.Level)
+    }
+
+    req.Header.Set("X-Level", "medium")
+
+    err = BindE(req, &tr)
+
+    var pe *ParseError
+    if errors.As(err, &pe) == false {
+        t.Fatalf("Expected parse error: [%v]", err)
+    } else if pe.Field() != "Level" || pe.Source() != SourceHeader || pe.Name() != "X-Level" {
+        t.Fatalf("Error not annotated correctly: [%s] [%s] [%s]", pe.Field(), pe.Source(), pe.Name())
+    } else if errors.Is(err, ErrSyntax) == false {
+        t.Fatalf("Expected syntax error: [%v]", err)
+    }
+}
+
+func TestParseAs_TextType(t *testing.T) {
+    if v := ParseAs[testLevel]("high"); v != 2 {
+        t.Fatalf("Value not correct: [%v]", v)
+    }
+
+    if v := ParseAs[net.IP]("::1"); v.Equal(net.IPv6loopback) == false {
+        t.Fatalf("Address not correct: [%s]", v)
+    }
+
+    _, err := ParseAsE[testLevel]("medium")
+    if errors.Is(err, ErrSyntax) == false {
+        t.Fatalf("Expected syntax error: [%v]", err)
+    }
+}
+
+// testCents is a custom kind's value type.
+type testCents int64
+
+func testCentsConvert(valueRaw interface{}) (value interface{}, err error) {
+    s, err := ParseE(valueRaw, "string")
+    if err != nil {
+        return nil, err
+    }
+
+    whole, fraction, _ := strings.Cut(s.(string), ".")
+    if len(fraction) != 2 {
+        return nil, fmt.Errorf("%w: amount [%s] must have two decimal places", ErrSyntax, s)
+    }
+
+    cents, err := ParseE(whole + fraction, "int64")
+    if err != nil {
+        return nil, err
+    }
+
+    return testCents(cents.(int64)), nil
+}
+
+func TestRegistry_AddKind(t *testing.T) {
+    r := NewRegistry()
+    r.AddKind("cents", reflect.TypeOf(testCents(0)), testCentsConvert)
+
+    value, err := r.ParseE("12.34", "cents")
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if value.(testCents) != 1234 {
+        t.Fatalf("Value not correct: [%v]", value)
+    }
+
+    _, err = r.ParseE("12.3", "cents")
+    if errors.Is(err, ErrSyntax) == false {
+        t.Fatalf("Expected syntax error: [%v]", err)
+    } else if err.(*ParseError).KindName() != "cents" {
+        t.Fatalf("Error kind not correct: [%s]", err.(*ParseError).KindName())
+    }
+
+    if _, err := r.ParseE(nil, "cents"); err != nil {
+        t.Fatalf("Parse of nil failed: [%s]", err)
+    }
+
+    if zeroType, found := r.kindType("cents"); found == false || zeroType != reflect.TypeOf(testCents(0)) {
+        t.Fatalf("Kind type not correct: [%v]", zeroType)
+    }
+
+    if _, found := NewRegistry().kindType("cents"); found == true {
+        t.Fatalf("Kind leaked into another registry.")
+    }
+
+    if _, found := r.Clone().kindType("cents"); found == false {
+        t.Fatalf("Kind not cloned.")
+    }
+}
+
+func TestRegistry_AddKind_Recovered(t *testing.T) {
+    r := NewRegistry()
+    r.AddKind("always-panics", reflect.TypeOf(""), func(valueRaw interface{}) (interface{}, error) {
+        panic(fmt.Errorf("%w: conversion panicked", ErrRange))
+    })
+
+    _, err := r.ParseE("abc", "always-panics")
+    if errors.Is(err, ErrRange) == false {
+        t.Fatalf("Expected range error: [%v]", err)
+    }
+}
+
+func TestRegistry_AddKind_WrongType(t *testing.T) {
+    r := NewRegistry()
+    r.AddKind("wrong", reflect.TypeOf(testCents(0)), func(valueRaw interface{}) (interface{}, error) {
+        return "not cents", nil
+    })
+
+    _, err := r.ParseE("abc", "wrong")
+    if errors.Is(err, ErrUnsupportedKind) == false {
+        t.Fatalf("Expected unsupported-kind error: [%v]", err)
+    }
+}
+
+func TestRegistry_AddKind_LossyType(t *testing.T) {
+    type testPort uint16
+
+    r := NewRegistry()
+    r.AddKind("port", reflect.TypeOf(testPort(0)), func(valueRaw interface{}) (interface{}, error) {
+        return valueRaw, nil
+    })
+
+    r.AddKind("name", reflect.TypeOf(""), func(valueRaw interface{}) (interface{}, error) {
+        return valueRaw, nil
+    })
+
+    r.AddKind("count", reflect.TypeOf(uint64(0)), func(valueRaw interface{}) (interface{}, error) {
+        return valueRaw, nil
+    })
+
+    r.AddKind("ratio", reflect.TypeOf(float32(0)), func(valueRaw interface{}) (interface{}, error) {
+        return valueRaw, nil
+    })
+
+    value, err := r.ParseE(8080, "port")
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if value.(testPort) != 8080 {
+        t.Fatalf("Value not correct: [%v]", value)
+    }
+
+    value, err = r.ParseE(testLevel(2), "count")
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if value.(uint64) != 2 {
+        t.Fatalf("Value not correct: [%v]", value)
+    }
+
+    value, err = r.ParseE(0.5, "ratio")
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if value.(float32) != 0.5 {
+        t.Fatalf("Value not correct: [%v]", value)
+    }
+
+    cases := []struct {
+        valueRaw interface{}
+        kindName string
+        expected error
+    }{
+        {70000, "port", ErrRange},
+        {-1, "count", ErrRange},
+        {65, "name", ErrUnsupportedKind},
+        {uint64(1) << 63, "port", ErrRange},
+        {1.5, "count", ErrUnsupportedKind},
+        {0.1, "ratio", ErrRange},
+    }
+
+    for _, c := range cases {
+        value, err := r.ParseE(c.valueRaw, c.kindName)
+        if errors.Is(err, c.expected) == false {
+            t.Fatalf("Expected [%v] for [%v] to kind [%s]: [%v] [%v]", c.expected, c.valueRaw, c.kindName, value, err)
+        }
+    }
+}
+
+func TestRegistry_AddKind_BuiltinKind(t *testing.T) {
+    defer func() {
+        if errRaw := recover(); errRaw == nil {
+            t.Fatalf("Expected panic for built-in kind.")
+        }
+    }()
+
+    NewRegistry().AddKind("int64", reflect.TypeOf(testCents(0)), testCentsConvert)
+}
+
+func TestRegistry_AddConversion(t *testing.T) {
+    type amount struct {
+        cents int64
+    }
+
+    r := NewRegistry()
+    r.AddConversion(reflect.TypeOf(amount{}), "int64", func(valueRaw interface{}) (interface{}, error) {
+        return valueRaw.(amount).cents, nil
+    })
+
+    value, err := r.ParseE(amount{ cents: 99 }, "int64")
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if value.(int64) != 99 {
+        t.Fatalf("Value not correct: [%v]", value)
+    }
+
+    // Only the registered pair is supported.
+    _, err = r.ParseE(amount{ cents: 99 }, "string")
+    if errors.Is(err, ErrUnsupportedKind) == false {
+        t.Fatalf("Expected unsupported-kind error: [%v]", err)
+    }
+}
+
+func TestRegistry_AddConversion_OverridesParser(t *testing.T) {
+    r := NewRegistry()
+    r.AddConversion(reflect.TypeOf(""), "bool", func(valueRaw interface{}) (interface{}, error) {
+        switch valueRaw.(string) {
+        case "yes":
+            return true, nil
+        case "no":
+            return false, nil
+        }
+
+        return nil, fmt.Errorf("%w: [%s] is not yes or no", ErrSyntax, valueRaw)
+    })
+
+    if value := r.Parse("yes", "bool"); value.(bool) != true {
+        t.Fatalf("Value not correct: [%v]", value)
+    }
+
+    if _, err := r.ParseE("true", "bool"); errors.Is(err, ErrSyntax) == false {
+        t.Fatalf("Expected syntax error: [%v]", err)
+    }
+
+    if value := r.Parse("123", "uint64"); value.(uint64) != 123 {
+        t.Fatalf("Other kinds should not be affected: [%v]", value)
+    }
+}
+
+func TestRegistry_AddConversion_UnknownKind(t *testing.T) {
+    defer func() {
+        if errRaw := recover(); errRaw == nil {
+            t.Fatalf("Expected panic for unknown kind.")
+        }
+    }()
+
+    NewRegistry().AddConversion(reflect.TypeOf(""), "invalid-kind", testCentsConvert)
+}
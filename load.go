@@ -0,0 +1,576 @@
+package parse
+
+import (
+    "fmt"
+    "reflect"
+    "strconv"
+    "strings"
+    "unicode"
+
+    "text/tabwriter"
+
+    "github.com/dsoprea/go-logging"
+)
+
+const (
+    // ConfigTagName is the struct-tag consulted by Load().
+    ConfigTagName = "config"
+
+    // SecretMask replaces the value of a secret field in a Report.
+    SecretMask = "******"
+)
+
+// configField describes a single field of a configuration struct. The name
+// of the embedded bindField is the key that is looked up (e.g. "db.host").
+// For a slice, the kind and default are those of the items.
+type configField struct {
+    bindField
+
+    secret bool
+
+    // envName is the environment variable from the "env" option, if any.
+    envName string
+
+    slice bool
+
+    // separator delimits the items of a slice that are given as a single
+    // value.
+    separator string
+}
+
+// configKey derives the key for a field that has no name in its tag from the
+// Go field name (e.g. "MaxConns" to "max-conns" and "HTTPPort" to
+// "http-port").
+func configKey(fieldName string) string {
+    runes := []rune(fieldName)
+    b := new(strings.Builder)
+
+    for i, r := range runes {
+        if i > 0 && unicode.IsUpper(r) == true {
+            previous := runes[i - 1]
+            nextIsLower := i + 1 < len(runes) && unicode.IsLower(runes[i + 1]) == true
+
+            if unicode.IsUpper(previous) == false || nextIsLower == true {
+                b.WriteRune('-')
+            }
+        }
+
+        b.WriteRune(unicode.ToLower(r))
+    }
+
+    return b.String()
+}
+
+// parseConfigTag parses a tag like "port,kind=uint16,default=8080". The
+// first part is the name, which may be empty.
+func parseConfigTag(path string, tag string) (cf configField, defaultRaw string, hasDefault bool, err error) {
+    cf.path = path
+
+    parts := strings.Split(tag, ",")
+    cf.name = strings.TrimSpace(parts[0])
+
+    for _, part := range parts[1:] {
+        part = strings.TrimSpace(part)
+        if part == "" {
+            continue
+        }
+
+        key, value, _ := strings.Cut(part, "=")
+
+        switch key {
+        case "kind":
+            cf.kindName = value
+        case "required":
+            cf.required = true
+        case "secret":
+            cf.secret = true
+        case "default":
+            defaultRaw = value
+            hasDefault = true
+        case "env", "sep":
+            if value == "" {
+                return cf, "", false, fmt.Errorf("field [%s] tag option [%s] has no value", path, key)
+            }
+
+            if key == "env" {
+                cf.envName = value
+            } else {
+                cf.separator = value
+            }
+        default:
+            return cf, "", false, fmt.Errorf("field [%s] tag option [%s] not valid", path, key)
+        }
+    }
+
+    if hasDefault == true && cf.required == true {
+        return cf, "", false, fmt.Errorf("field [%s] is required but has a default", path)
+    }
+
+    return cf, defaultRaw, hasDefault, nil
+}
+
+// splitItems splits a delimited value into the items of a slice. Space
+// around the items and empty items are dropped.
+func splitItems(s, separator string) (items []string) {
+    for _, item := range strings.Split(s, separator) {
+        item = strings.TrimSpace(item)
+        if item != "" {
+            items = append(items, item)
+        }
+    }
+
+    return items
+}
+
+// resolveSliceKind resolves the kind of the items of a slice field. The
+// default is split into items like a value.
+func (cf *configField) resolveSliceKind(t reflect.Type, defaultRaw string, hasDefault bool) (err error) {
+    if cf.separator == "" {
+        cf.separator = ","
+    }
+
+    err = cf.resolveKind(t.Elem(), "", false)
+    if err != nil {
+        return err
+    } else if hasDefault == false {
+        return nil
+    }
+
+    values := make([]interface{}, 0)
+    for _, item := range splitItems(defaultRaw, cf.separator) {
+        value, err := cf.parseDefault(item)
+        if err != nil {
+            return fmt.Errorf("field [%s] default [%s] not valid: %w", cf.path, defaultRaw, err)
+        }
+
+        values = append(values, value)
+    }
+
+    cf.defaultValue = values
+
+    return nil
+}
+
+// collectConfigFields finds the fields of the given configuration struct
+// type. Every exported field is included unless it is tagged "-". Struct
+// fields (and pointers to structs) without an explicit kind are descended
+// into, and their key is the prefix of the keys of their fields. Embedded
+// structs without a name in their tag are flattened into the struct that
+// embeds them. `parents` are the struct types that contain `t`; a struct that
+// contains itself (e.g. a linked list) can not be loaded and is an error.
+func collectConfigFields(t reflect.Type, prefix, keyPrefix string, index []int, parents []reflect.Type, fields []configField) ([]configField, error) {
+    parents = append(parents[:len(parents):len(parents)], t)
+
+    for i := 0; i < t.NumField(); i++ {
+        sf := t.Field(i)
+
+        structType := sf.Type
+        if structType.Kind() == reflect.Ptr {
+            structType = structType.Elem()
+        }
+
+        isStruct := structType.Kind() == reflect.Struct && structType != timeType && isTextType(structType) == false
+
+        // The exported fields of an unexported embedded struct are still
+        // promoted. An unexported embedded pointer can not be allocated,
+        // though.
+        if sf.IsExported() == false && (sf.Anonymous == false || isStruct == false || sf.Type.Kind() == reflect.Ptr) {
+            continue
+        }
+
+        path := sf.Name
+        if prefix != "" {
+            path = prefix + "." + sf.Name
+        }
+
+        fieldIndex := make([]int, len(index) + 1)
+        copy(fieldIndex, index)
+        fieldIndex[len(index)] = i
+
+        tag := sf.Tag.Get(ConfigTagName)
+        if tag == "-" {
+            continue
+        }
+
+        cf, defaultRaw, hasDefault, err := parseConfigTag(path, tag)
+        if err != nil {
+            return nil, err
+        }
+
+        flatten := sf.Anonymous == true && cf.name == ""
+
+        if cf.name == "" {
+            cf.name = configKey(sf.Name)
+        }
+
+        if keyPrefix != "" {
+            cf.name = keyPrefix + "." + cf.name
+        }
+
+        if isStruct == true && cf.kindName == "" {
+            if cf.required == true || cf.secret == true || hasDefault == true || cf.envName != "" || cf.separator != "" {
+                return nil, fmt.Errorf("field [%s] is a struct and only takes a name", path)
+            }
+
+            for _, parent := range parents {
+                if parent == structType {
+                    return nil, fmt.Errorf("field [%s] of type [%s] contains itself", path, structType)
+                }
+            }
+
+            childPrefix := cf.name
+            if flatten == true {
+                childPrefix = keyPrefix
+            }
+
+            fields, err = collectConfigFields(structType, path, childPrefix, fieldIndex, parents, fields)
+            if err != nil {
+                return nil, err
+            }
+
+            continue
+        }
+
+        if sf.Type.Kind() == reflect.Slice && isTextType(sf.Type) == false {
+            cf.slice = true
+            err = cf.resolveSliceKind(sf.Type, defaultRaw, hasDefault)
+        } else if cf.separator != "" {
+            err = fmt.Errorf("field [%s] is not a slice and does not take a separator", path)
+        } else {
+            err = cf.resolveKind(sf.Type, defaultRaw, hasDefault)
+        }
+
+        if err != nil {
+            return nil, err
+        }
+
+        cf.index = fieldIndex
+        fields = append(fields, cf)
+    }
+
+    return fields, nil
+}
+
+// envKey returns the environment variable to look up for a name derived
+// from the key of a field with an "env" option: the key itself or, for a
+// slice, an indexed key (e.g. "DATABASE_HOSTS_0" for "db.hosts.0").
+func (cf configField) envKey(name string) string {
+    return cf.envName + environName("", strings.TrimPrefix(name, cf.name))
+}
+
+// steps returns the chain steps to look the field up in. An environment
+// source looks up the variable from the "env" option as-is, if there is one.
+func (cf configField) steps(sources []Source) []ChainStep {
+    steps := make([]ChainStep, len(sources))
+
+    for i, source := range sources {
+        steps[i] = ChainStep{ Source: source }
+
+        if _, ok := source.(*EnvironSource); ok == true && cf.envName != "" {
+            steps[i] = ChainStep{ Source: NewEnvironSource(), Key: cf.envKey }
+        }
+    }
+
+    return steps
+}
+
+// getSliceField looks up the items of a slice field in each step in turn.
+// The items are either a single delimited value (or an array in a decoded
+// file) or a series of indexed values (e.g. "hosts.0", "hosts.1", etc., which
+// are APP_HOSTS_0, APP_HOSTS_1, etc. in a prefixed environment source).
+// `origin` is where the value or the first indexed value was found.
+func getSliceField(steps []ChainStep, cf configField) (values []interface{}, origin Origin, err error) {
+    item := cf.bindField
+    item.required = true
+
+    for _, step := range steps {
+        key := func(name string) string {
+            if step.Key == nil {
+                return name
+            }
+
+            return step.Key(name)
+        }
+
+        valueRaw, present, found := lookupOrigin(step.Source, key(cf.name))
+        if present == true {
+            if isEmpty(valueRaw) == true {
+                // As with a ChainSource, an empty value ends the search.
+                break
+            }
+
+            var items []interface{}
+
+            switch v := valueRaw.(type) {
+            case string:
+                for _, s := range splitItems(v, cf.separator) {
+                    items = append(items, s)
+                }
+            case []interface{}:
+                items = v
+            default:
+                items = []interface{} { valueRaw }
+            }
+
+            for _, itemRaw := range items {
+                value, _, err := parseField(item, found, itemRaw, true)
+                if err != nil {
+                    return nil, Origin{}, err
+                }
+
+                values = append(values, value)
+            }
+
+            return values, found, nil
+        }
+
+        for i := 0; ; i++ {
+            valueRaw, present, found := lookupOrigin(step.Source, key(fmt.Sprintf("%s.%d", cf.name, i)))
+            if present == false {
+                break
+            } else if i == 0 {
+                origin = found
+            }
+
+            value, _, err := parseField(item, found, valueRaw, true)
+            if err != nil {
+                return nil, Origin{}, err
+            }
+
+            values = append(values, value)
+        }
+
+        if len(values) > 0 {
+            return values, origin, nil
+        }
+    }
+
+    if cf.required == true {
+        return nil, Origin{}, newMissingError(SourceChain, cf.name, cf.kindName, missingMessage(SourceChain, cf.name))
+    }
+
+    return nil, Origin{}, nil
+}
+
+// allocFieldByIndex returns the field at the index, allocating any nil
+// pointers to nested structs on the way. They are only allocated once one of
+// their fields has a value.
+func allocFieldByIndex(v reflect.Value, index []int) reflect.Value {
+    for i, x := range index {
+        if i > 0 && v.Kind() == reflect.Ptr {
+            if v.IsNil() == true {
+                v.Set(reflect.New(v.Type().Elem()))
+            }
+
+            v = v.Elem()
+        }
+
+        v = v.Field(x)
+    }
+
+    return v
+}
+
+// setSliceField stores the parsed items of a slice field.
+func setSliceField(fv reflect.Value, values []interface{}) {
+    sv := reflect.MakeSlice(fv.Type(), len(values), len(values))
+    for i, value := range values {
+        setField(sv.Index(i), value)
+    }
+
+    fv.Set(sv)
+}
+
+// ReportEntry describes where the value of a single configuration field came
+// from.
+type ReportEntry struct {
+    // Field is the dotted Go field path (e.g. "DB.Host").
+    Field string
+
+    // Name is the key that was looked up in the sources (e.g. "db.host").
+    Name string
+
+    // Value is the final value of the field (e.g. `"localhost"` or
+    // `[1, 2]`). It is SecretMask for a secret field that is not empty.
+    Value string
+
+    Secret bool
+
+    // Origin is where the value was found (for indexed slice items, the
+    // first one). Origin.Source is SourceDefault if the default was used,
+    // and empty if the field was left as it was.
+    Origin Origin
+}
+
+// Report lists the fields that Load() populated, in field order.
+type Report []ReportEntry
+
+// String returns the report as a table, suitable for logging at startup.
+func (r Report) String() string {
+    b := new(strings.Builder)
+    tw := tabwriter.NewWriter(b, 0, 4, 2, ' ', 0)
+
+    fmt.Fprintf(tw, "NAME\tVALUE\tSOURCE\tKEY\n")
+
+    for _, re := range r {
+        source := re.Origin.Source
+        key := re.Origin.Name
+
+        if source == "" {
+            source = "unset"
+            key = "-"
+        }
+
+        fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", re.Name, re.Value, source, key)
+    }
+
+    tw.Flush()
+
+    return b.String()
+}
+
+// format formats the value of the field for the report.
+func (cf configField) format(fv reflect.Value) string {
+    if cf.secret == true && fv.IsZero() == false {
+        return SecretMask
+    } else if cf.slice == false {
+        return formatConfigValue(fv)
+    }
+
+    items := make([]string, fv.Len())
+    for i := range items {
+        items[i] = formatConfigValue(fv.Index(i))
+    }
+
+    return "[" + strings.Join(items, ", ") + "]"
+}
+
+// formatConfigValue formats a value for the report. Strings are quoted so
+// that an empty one is visible.
+func formatConfigValue(fv reflect.Value) string {
+    if fv.Kind() == reflect.Ptr {
+        if fv.IsNil() == true {
+            return "<nil>"
+        }
+
+        fv = fv.Elem()
+    }
+
+    if fv.Kind() == reflect.String {
+        return strconv.Quote(fv.String())
+    }
+
+    return fmt.Sprintf("%v", fv.Interface())
+}
+
+// Load populates the struct that `dst` points to from the sources and
+// returns where each value came from. It panics on failure.
+func Load(dst interface{}, sources ...Source) (report Report) {
+    report, err := LoadE(dst, sources...)
+    log.PanicIf(err)
+
+    return report
+}
+
+// LoadE is the error-returning variant of Load. Every exported field is
+// loaded, descending into struct fields and pointers to structs (which are
+// allocated once one of their fields has a value). Fields are tagged like:
+//
+//     Host string `config:"host,required"`
+//     Port uint16 `config:",default=5432"`
+//     Password string `config:"password,secret,env=DB_PASSWORD"`
+//     Peers []string `config:",sep=;"`
+//     Limit int32 `config:"-"`
+//
+// The first part of the tag is the key, which defaults to the field name in
+// lower kebab-case (e.g. "max-conns"). The key of a field of a nested struct
+// is prefixed with the key of that struct (e.g. "db.host"), except that an
+// embedded struct without a name in its tag is flattened. The sources are
+// searched in order for each key (see ChainSource), so pass the ones with
+// the highest precedence first. A NewPrefixedEnvironSource() looks keys up
+// as prefixed upper-snake-case variables (e.g. APP_DB_HOST), and the "env"
+// option gives the whole variable name for a field instead. Kinds, text
+// types and defaults are as for Bind(). Fields that are absent and have no
+// default are left as they were.
+//
+// A slice is given either as one value delimited by the "sep" option (","
+// by default; it also splits the default) or as indexed values (e.g.
+// "peers.0", "peers.1", etc.). The kind applies to the items.
+//
+// Parse failures are returned together as a ParseErrors, along with the
+// report of the other fields. An invalid destination or struct-tag is
+// returned immediately.
+func LoadE(dst interface{}, sources ...Source) (report Report, err error) {
+    v, err := structValue(dst)
+    if err != nil {
+        return nil, err
+    }
+
+    fields, err := collectConfigFields(v.Type(), "", "", nil, nil, nil)
+    if err != nil {
+        return nil, err
+    }
+
+    report = make(Report, 0, len(fields))
+    var pes ParseErrors
+
+    for _, cf := range fields {
+        steps := cf.steps(sources)
+
+        var value interface{}
+        var origin Origin
+
+        if cf.slice == true {
+            var values []interface{}
+
+            values, origin, err = getSliceField(steps, cf)
+            if values != nil {
+                value = values
+            }
+        } else {
+            value, origin, err = getField(NewChainSource(steps...), cf.bindField)
+        }
+
+        if err != nil {
+            pe, ok := err.(*ParseError)
+            if ok == false {
+                return nil, err
+            }
+
+            pes = append(pes, pe.withPath(cf.path))
+            continue
+        } else if value == nil && cf.defaultValue != nil {
+            value = cf.defaultValue
+            origin = Origin{ Source: SourceDefault, Name: cf.name }
+        }
+
+        if value != nil && cf.slice == true {
+            setSliceField(allocFieldByIndex(v, cf.index), value.([]interface{}))
+        } else if value != nil {
+            setField(allocFieldByIndex(v, cf.index), value)
+        }
+
+        // A field of a nested struct that was not allocated is reported as
+        // the zero-value.
+        fv, indexErr := v.FieldByIndexErr(cf.index)
+        if indexErr != nil {
+            fv = reflect.Zero(v.Type().FieldByIndex(cf.index).Type)
+        }
+
+        re := ReportEntry{
+            Field: cf.path,
+            Name: cf.name,
+            Value: cf.format(fv),
+            Secret: cf.secret,
+            Origin: origin,
+        }
+
+        report = append(report, re)
+    }
+
+    if len(pes) > 0 {
+        return report, pes
+    }
+
+    return report, nil
+}
@@ -0,0 +1,398 @@
+package parse
+
+import (
+    "testing"
+    "errors"
+    "reflect"
+    "strings"
+
+    "flag"
+    "net"
+)
+
+type testServiceConfig struct {
+    DB struct {
+        Host string `config:"host,required"`
+        Port uint16 `config:",default=5432"`
+        Password string `config:"password,secret"`
+        MaxConns *int32
+    } `config:"db"`
+
+    Listen net.IP `config:",default=127.0.0.1"`
+    Level testLevel
+    Debug bool
+
+    Internal string `config:"-"`
+}
+
+func TestConfigKey(t *testing.T) {
+    cases := map[string]string {
+        "Host": "host",
+        "DB": "db",
+        "MaxConns": "max-conns",
+        "HTTPPort": "http-port",
+        "APIKey": "api-key",
+        "Port2": "port2",
+    }
+
+    for fieldName, expected := range cases {
+        if key := configKey(fieldName); key != expected {
+            t.Fatalf("Key for [%s] not correct: [%s] != [%s]", fieldName, key, expected)
+        }
+    }
+}
+
+func TestLoadE(t *testing.T) {
+    fs := flag.NewFlagSet("test", flag.ContinueOnError)
+    fs.Bool("debug", false, "")
+
+    if err := fs.Parse([]string { "--debug" }); err != nil {
+        t.Fatalf("Could not parse flags: [%s]", err)
+    }
+
+    file := map[string]interface{} {
+        "db": map[string]interface{} {
+            "host": "file-host",
+            "max-conns": "20",
+        },
+        "level": "high",
+    }
+
+    env := map[string]string {
+        "db.host": "env-host",
+        "db.password": "hunter2",
+    }
+
+    config := testServiceConfig{}
+    config.Internal = "untouched"
+
+    report, err := LoadE(&config, NewFlagSource(fs), NewMapSource(env), NewInterfaceMapSource(file))
+    if err != nil {
+        t.Fatalf("Load failed: [%s]", err)
+    }
+
+    if config.DB.Host != "env-host" {
+        t.Fatalf("Host not correct: [%s]", config.DB.Host)
+    } else if config.DB.Port != 5432 {
+        t.Fatalf("Default port not used: (%d)", config.DB.Port)
+    } else if config.DB.Password != "hunter2" {
+        t.Fatalf("Password not correct: [%s]", config.DB.Password)
+    } else if config.DB.MaxConns == nil || *config.DB.MaxConns != 20 {
+        t.Fatalf("Max-connections not correct: %v", config.DB.MaxConns)
+    } else if config.Listen.Equal(net.IPv4(127, 0, 0, 1)) == false {
+        t.Fatalf("Listen address not correct: [%s]", config.Listen)
+    } else if config.Level != 2 {
+        t.Fatalf("Level not correct: (%d)", config.Level)
+    } else if config.Debug != true {
+        t.Fatalf("Debug not correct.")
+    } else if config.Internal != "untouched" {
+        t.Fatalf("Excluded field was changed: [%s]", config.Internal)
+    }
+
+    expected := Report{
+        { Field: "DB.Host", Name: "db.host", Value: `"env-host"`, Origin: Origin{ Source: SourceMap, Name: "db.host" } },
+        { Field: "DB.Port", Name: "db.port", Value: "5432", Origin: Origin{ Source: SourceDefault, Name: "db.port" } },
+        { Field: "DB.Password", Name: "db.password", Value: SecretMask, Secret: true, Origin: Origin{ Source: SourceMap, Name: "db.password" } },
+        { Field: "DB.MaxConns", Name: "db.max-conns", Value: "20", Origin: Origin{ Source: SourceMap, Name: "db.max-conns" } },
+        { Field: "Listen", Name: "listen", Value: "127.0.0.1", Origin: Origin{ Source: SourceDefault, Name: "listen" } },
+        { Field: "Level", Name: "level", Value: "2", Origin: Origin{ Source: SourceMap, Name: "level" } },
+        { Field: "Debug", Name: "debug", Value: "true", Origin: Origin{ Source: SourceFlag, Name: "debug" } },
+    }
+
+    if len(report) != len(expected) {
+        t.Fatalf("Report not correct: %v", report)
+    }
+
+    for i, re := range report {
+        if re != expected[i] {
+            t.Fatalf("Report entry (%d) not correct: %v != %v", i, re, expected[i])
+        }
+    }
+
+    s := report.String()
+    if strings.Contains(s, "hunter2") == true {
+        t.Fatalf("Report shows secret:\n%s", s)
+    }
+
+    lines := strings.Split(strings.TrimSpace(s), "\n")
+    if len(lines) != len(expected) + 1 {
+        t.Fatalf("Report table not correct:\n%s", s)
+    } else if strings.Fields(lines[1])[0] != "db.host" || strings.Fields(lines[1])[2] != SourceMap {
+        t.Fatalf("Report row not correct: [%s]", lines[1])
+    }
+}
+
+func TestLoadE_Unset(t *testing.T) {
+    config := struct {
+        Name string
+        Secret string `config:",secret"`
+    }{
+        Name: "preset",
+    }
+
+    report, err := LoadE(&config, NewMapSource(nil))
+    if err != nil {
+        t.Fatalf("Load failed: [%s]", err)
+    } else if config.Name != "preset" {
+        t.Fatalf("Absent field was changed: [%s]", config.Name)
+    }
+
+    if report[0].Origin.Source != "" || report[0].Value != `"preset"` {
+        t.Fatalf("Unset entry not correct: %v", report[0])
+    } else if report[1].Value != `""` {
+        t.Fatalf("Empty secret should not be masked: %v", report[1])
+    }
+
+    if lines := strings.Split(report.String(), "\n"); strings.Fields(lines[1])[2] != "unset" {
+        t.Fatalf("Unset row not correct: [%s]", lines[1])
+    }
+}
+
+func TestLoadE_Errors(t *testing.T) {
+    env := map[string]string {
+        "db.port": "abc",
+        "level": "medium",
+    }
+
+    config := testServiceConfig{}
+
+    report, err := LoadE(&config, NewMapSource(env))
+
+    var pes ParseErrors
+    if errors.As(err, &pes) == false {
+        t.Fatalf("Expected ParseErrors: [%v]", err)
+    } else if len(pes) != 3 {
+        t.Fatalf("Expected three errors: %v", pes)
+    }
+
+    if errors.Is(pes[0], ErrMissing) == false || pes[0].Field() != "DB.Host" {
+        t.Fatalf("Missing error not correct: [%s]", pes[0])
+    } else if pes[0].Source() != SourceChain || pes[0].Name() != "db.host" {
+        t.Fatalf("Missing error should be reported against the chain: [%s] [%s]", pes[0].Source(), pes[0].Name())
+    } else if errors.Is(pes[1], ErrSyntax) == false || pes[1].Field() != "DB.Port" || pes[1].Source() != SourceMap {
+        t.Fatalf("Port error not correct: [%s]", pes[1])
+    } else if errors.Is(pes[2], ErrSyntax) == false || pes[2].Field() != "Level" {
+        t.Fatalf("Level error not correct: [%s]", pes[2])
+    }
+
+    // The fields that could be loaded are still reported.
+    if len(report) != 4 {
+        t.Fatalf("Partial report not correct: %v", report)
+    }
+}
+
+func TestLoadE_InvalidTag(t *testing.T) {
+    config := struct {
+        Sub struct {
+            Value string
+        } `config:"sub,secret"`
+    }{}
+
+    _, err := LoadE(&config)
+    if err == nil || err.Error() != "field [Sub] is a struct and only takes a name" {
+        t.Fatalf("Expected struct tag error: [%v]", err)
+    }
+
+    invalid := struct {
+        Port uint16 `config:",default=x"`
+    }{}
+
+    if _, err := LoadE(&invalid); err == nil || strings.HasPrefix(err.Error(), "field [Port] default [x] not valid") == false {
+        t.Fatalf("Expected default error: [%v]", err)
+    }
+
+    lossy := struct {
+        Level int `config:",kind=float64"`
+    }{}
+
+    if _, err := LoadE(&lossy); err == nil || err.Error() != "field [Level] kind [float64] can not be stored in type [int]" {
+        t.Fatalf("Expected kind error: [%v]", err)
+    }
+}
+
+type testDBConfig struct {
+    Host string
+    Port uint16
+}
+
+type testNode struct {
+    Name string
+    Next *testNode
+}
+
+func TestLoadE_Cycle(t *testing.T) {
+    node := testNode{}
+
+    _, err := LoadE(&node)
+    if err == nil || err.Error() != "field [Next] of type [parse.testNode] contains itself" {
+        t.Fatalf("Expected cycle error: [%v]", err)
+    }
+
+    config := struct {
+        Primary testDBConfig
+        Replica *testDBConfig
+    }{}
+
+    // The same type may be used more than once if it does not contain
+    // itself.
+    if _, err := LoadE(&config); err != nil {
+        t.Fatalf("Load failed: [%s]", err)
+    }
+}
+
+func TestLoadE_StructPointer(t *testing.T) {
+    config := struct {
+        DB *testDBConfig
+        Cache *testDBConfig
+    }{}
+
+    env := map[string]string {
+        "db.host": "db.internal",
+    }
+
+    report, err := LoadE(&config, NewMapSource(env))
+    if err != nil {
+        t.Fatalf("Load failed: [%s]", err)
+    } else if config.DB == nil || config.DB.Host != "db.internal" || config.DB.Port != 0 {
+        t.Fatalf("Pointer to struct not loaded: %v", config.DB)
+    } else if config.Cache != nil {
+        t.Fatalf("Pointer to struct without values should not be allocated: %v", config.Cache)
+    }
+
+    if len(report) != 4 {
+        t.Fatalf("Report not correct: %v", report)
+    } else if report[2].Name != "cache.host" || report[2].Value != `""` || report[2].Origin.Source != "" {
+        t.Fatalf("Unallocated field not reported correctly: %v", report[2])
+    }
+}
+
+type testCommonConfig struct {
+    Region string
+}
+
+type testCredentials struct {
+    Token string `config:",secret"`
+}
+
+type testEnvironConfig struct {
+    testCommonConfig
+    testCredentials
+
+    DB struct {
+        Host string `config:",env=MULTIPARSE_DATABASE_HOST"`
+        Replicas []string `config:",env=MULTIPARSE_DATABASE_REPLICAS"`
+    }
+
+    Peers []string `config:",sep=;"`
+    Ports []uint16
+    Weights []float64 `config:",default=0.5;1.5,sep=;"`
+    Levels []testLevel
+}
+
+func TestLoadE_Environ(t *testing.T) {
+    t.Setenv("MULTIPARSE_TEST_REGION", "eu-west")
+    t.Setenv("MULTIPARSE_TEST_TOKEN", "abc123")
+    t.Setenv("MULTIPARSE_DATABASE_HOST", "db.internal")
+    t.Setenv("MULTIPARSE_TEST_DB_HOST", "ignored")
+    t.Setenv("MULTIPARSE_DATABASE_REPLICAS_0", "r1")
+    t.Setenv("MULTIPARSE_DATABASE_REPLICAS_1", "r2")
+    t.Setenv("MULTIPARSE_TEST_PEERS", "a:1; b:2;")
+    t.Setenv("MULTIPARSE_TEST_PORTS_0", "80")
+    t.Setenv("MULTIPARSE_TEST_PORTS_1", "443")
+    t.Setenv("MULTIPARSE_TEST_PORTS_3", "8080")
+
+    file := map[string]interface{} {
+        "peers": "c:3",
+        "levels": []interface{} { "low", "high" },
+    }
+
+    config := testEnvironConfig{}
+
+    report, err := LoadE(&config, NewPrefixedEnvironSource("MULTIPARSE_TEST"), NewInterfaceMapSource(file))
+    if err != nil {
+        t.Fatalf("Load failed: [%s]", err)
+    }
+
+    if config.Region != "eu-west" || config.Token != "abc123" {
+        t.Fatalf("Embedded fields not correct: [%s] [%s]", config.Region, config.Token)
+    } else if config.DB.Host != "db.internal" {
+        t.Fatalf("Overridden name not used: [%s]", config.DB.Host)
+    } else if reflect.DeepEqual(config.DB.Replicas, []string { "r1", "r2" }) == false {
+        t.Fatalf("Overridden indexed slice not correct: %v", config.DB.Replicas)
+    } else if reflect.DeepEqual(config.Peers, []string { "a:1", "b:2" }) == false {
+        t.Fatalf("Delimited slice not correct: %v", config.Peers)
+    } else if reflect.DeepEqual(config.Ports, []uint16 { 80, 443 }) == false {
+        t.Fatalf("Indexed slice not correct: %v", config.Ports)
+    } else if reflect.DeepEqual(config.Weights, []float64 { 0.5, 1.5 }) == false {
+        t.Fatalf("Default slice not correct: %v", config.Weights)
+    } else if reflect.DeepEqual(config.Levels, []testLevel { 1, 2 }) == false {
+        t.Fatalf("Array slice not correct: %v", config.Levels)
+    }
+
+    expected := []struct {
+        name string
+        value string
+        origin Origin
+    }{
+        { "region", `"eu-west"`, Origin{ Source: SourceEnviron, Name: "MULTIPARSE_TEST_REGION" } },
+        { "token", SecretMask, Origin{ Source: SourceEnviron, Name: "MULTIPARSE_TEST_TOKEN" } },
+        { "db.host", `"db.internal"`, Origin{ Source: SourceEnviron, Name: "MULTIPARSE_DATABASE_HOST" } },
+        { "db.replicas", `["r1", "r2"]`, Origin{ Source: SourceEnviron, Name: "MULTIPARSE_DATABASE_REPLICAS_0" } },
+        { "peers", `["a:1", "b:2"]`, Origin{ Source: SourceEnviron, Name: "MULTIPARSE_TEST_PEERS" } },
+        { "ports", "[80, 443]", Origin{ Source: SourceEnviron, Name: "MULTIPARSE_TEST_PORTS_0" } },
+        { "weights", "[0.5, 1.5]", Origin{ Source: SourceDefault, Name: "weights" } },
+        { "levels", "[1, 2]", Origin{ Source: SourceMap, Name: "levels" } },
+    }
+
+    if len(report) != len(expected) {
+        t.Fatalf("Report not correct: %v", report)
+    }
+
+    for i, re := range report {
+        e := expected[i]
+        if re.Name != e.name || re.Value != e.value || re.Origin != e.origin {
+            t.Fatalf("Report entry (%d) not correct: %v", i, re)
+        }
+    }
+}
+
+func TestLoadE_SliceErrors(t *testing.T) {
+    t.Setenv("MULTIPARSE_TEST_PORTS_0", "80")
+    t.Setenv("MULTIPARSE_TEST_PORTS_1", "http")
+
+    config := struct {
+        Ports []uint16
+        Hosts []string `config:",required"`
+    }{}
+
+    _, err := LoadE(&config, NewPrefixedEnvironSource("MULTIPARSE_TEST"))
+
+    var pes ParseErrors
+    if errors.As(err, &pes) == false || len(pes) != 2 {
+        t.Fatalf("Expected two errors: [%v]", err)
+    }
+
+    if errors.Is(pes[0], ErrSyntax) == false || pes[0].Field() != "Ports" || pes[0].Name() != "MULTIPARSE_TEST_PORTS_1" {
+        t.Fatalf("Item error not correct: [%s] [%s]", pes[0], pes[0].Name())
+    } else if errors.Is(pes[1], ErrMissing) == false || pes[1].Field() != "Hosts" || pes[1].Name() != "hosts" {
+        t.Fatalf("Missing error not correct: [%s]", pes[1])
+    }
+
+    invalid := struct {
+        Port uint16 `config:",sep=;"`
+    }{}
+
+    if _, err := LoadE(&invalid); err == nil || err.Error() != "field [Port] is not a slice and does not take a separator" {
+        t.Fatalf("Expected separator error: [%v]", err)
+    }
+}
+
+func TestLoad_Panic(t *testing.T) {
+    defer func() {
+        if state := recover(); state == nil {
+            t.Fatalf("Expected panic for invalid destination.")
+        }
+    }()
+
+    Load(testServiceConfig{})
+}
@@ -0,0 +1,208 @@
+package parse
+
+import (
+    "fmt"
+
+    "github.com/dsoprea/go-logging"
+)
+
+// EmptyPolicy determines how a value that is present but empty (e.g.
+// `?flag=` or a set-but-empty environment variable) is treated.
+type EmptyPolicy int
+
+const (
+    // EmptyAsMissing treats an empty value as if it were absent. This is
+    // the default.
+    EmptyAsMissing EmptyPolicy = iota
+
+    // EmptyAsValue parses an empty value like any other. This produces ""
+    // for "string" and fails with ErrSyntax for most other kinds.
+    EmptyAsValue
+
+    // EmptyAsZero produces the zero-value of the kind for an empty value.
+    EmptyAsZero
+)
+
+// String returns the name of the policy.
+func (ep EmptyPolicy) String() string {
+    switch ep {
+    case EmptyAsMissing:
+        return "EmptyAsMissing"
+    case EmptyAsValue:
+        return "EmptyAsValue"
+    case EmptyAsZero:
+        return "EmptyAsZero"
+    }
+
+    return fmt.Sprintf("EmptyPolicy(%d)", int(ep))
+}
+
+// options are the settings for a single lookup.
+type options struct {
+    emptyPolicy EmptyPolicy
+
+    // defaultKindName is the kind that defaultRaw was validated as, if a
+    // default was given.
+    defaultKindName string
+    defaultRaw interface{}
+
+    // origin is set by WithOrigin().
+    origin *Origin
+}
+
+// Option configures a single lookup (e.g. FromRequestQuery()).
+type Option func(o *options)
+
+// WithEmptyPolicy sets how a value that is present but empty is treated. See
+// EmptyPolicy.
+func WithEmptyPolicy(ep EmptyPolicy) Option {
+    return func(o *options) {
+        o.emptyPolicy = ep
+    }
+}
+
+// WithDefault produces the given default when an optional value is absent.
+// The default is validated as the given kind immediately, so create the
+// option once (e.g. in a package-level variable) for an invalid default to
+// fail at startup rather than when a request is served. It is parsed again
+// whenever it is used, so values of reference types (e.g. a net.IP) are not
+// shared between calls. It panics if the default is not valid. A required
+// value that is absent is still an error.
+func WithDefault(kindName string, valueRaw interface{}) Option {
+    opt, err := WithDefaultE(kindName, valueRaw)
+    log.PanicIf(err)
+
+    return opt
+}
+
+// WithDefaultE is the error-returning variant of WithDefault.
+func WithDefaultE(kindName string, valueRaw interface{}) (opt Option, err error) {
+    if valueRaw == nil {
+        return nil, fmt.Errorf("default for kind [%s] is nil", kindName)
+    }
+
+    _, err = ParseE(valueRaw, kindName)
+    if err != nil {
+        return nil, err
+    }
+
+    opt = func(o *options) {
+        o.defaultKindName = kindName
+        o.defaultRaw = valueRaw
+    }
+
+    return opt, nil
+}
+
+// Origin records where a value was found.
+type Origin struct {
+    // Source is the description of the source that supplied the value (e.g.
+    // SourceEnviron), or SourceDefault. It is empty if the value was absent.
+    Source string
+
+    // Name is the name that the value was found under in that source.
+    Name string
+}
+
+// WithOrigin records where the value was found in `origin`. This is mostly
+// useful with a ChainSource, where the value may come from any of several
+// sources.
+func WithOrigin(origin *Origin) Option {
+    return func(o *options) {
+        o.origin = origin
+    }
+}
+
+// setOrigin records where the value was found, if requested.
+func (o options) setOrigin(origin Origin) {
+    if o.origin != nil {
+        *o.origin = origin
+    }
+}
+
+// newOptions applies the given options to the defaults.
+func newOptions(opts []Option) (o options) {
+    for _, opt := range opts {
+        opt(&o)
+    }
+
+    return o
+}
+
+// isEmpty indicates whether a value that was found is empty. A JSON null is
+// considered empty.
+func isEmpty(valueRaw interface{}) bool {
+    return valueRaw == nil || valueRaw == ""
+}
+
+// mismatchError reclassifies an ErrUnsupportedKind failure for a known kind
+// as ErrSyntax. The value came from a source (e.g. a JSON array in a request
+// body), so a type that can not be parsed to the kind is bad input rather than
+// a programming error. Unknown kinds are left as they are.
+func mismatchError(err error, valueRaw interface{}, kindName string) error {
+    pe, ok := err.(*ParseError)
+    if ok == false || pe.cause != ErrUnsupportedKind {
+        return err
+    } else if _, found := DefaultRegistry.kindType(kindName); found == false {
+        return err
+    }
+
+    return newParseError(kindName, valueRaw, ErrSyntax, nil, fmt.Sprintf("value of type [%T] can not be parsed as kind [%s]", valueRaw, kindName))
+}
+
+// resolve parses a value that was looked up in a source. `origin` is where
+// the value was found or, if it is absent (`present` is false), the source
+// that was searched. An absent optional value produces the default, if one
+// was given, or nil.
+func resolve(origin Origin, kindName string, required bool, valueRaw interface{}, present bool, opts []Option) (value interface{}, err error) {
+    o := newOptions(opts)
+    o.setOrigin(Origin{})
+
+    source := origin.Source
+    name := origin.Name
+
+    if present == true && isEmpty(valueRaw) == true {
+        switch o.emptyPolicy {
+        case EmptyAsMissing:
+            present = false
+        case EmptyAsZero:
+            value, err = DefaultRegistry.zeroValue(kindName)
+            if err != nil {
+                return nil, annotateError(err, source, name)
+            }
+
+            o.setOrigin(origin)
+
+            return value, nil
+        }
+    }
+
+    if present == false {
+        if required == true {
+            return nil, newMissingError(source, name, kindName, missingMessage(source, name))
+        } else if o.defaultRaw == nil {
+            return nil, nil
+        } else if o.defaultKindName != kindName {
+            pe := newParseError(kindName, o.defaultRaw, ErrUnsupportedKind, nil, fmt.Sprintf("default for kind [%s] can not be used for kind [%s]", o.defaultKindName, kindName))
+            return nil, pe.withField(source, name)
+        }
+
+        value, err = ParseE(o.defaultRaw, kindName)
+        if err != nil {
+            return nil, annotateError(err, SourceDefault, name)
+        }
+
+        o.setOrigin(Origin{ Source: SourceDefault, Name: name })
+
+        return value, nil
+    }
+
+    value, err = ParseE(valueRaw, kindName)
+    if err != nil {
+        return nil, annotateError(mismatchError(err, valueRaw, kindName), source, name)
+    }
+
+    o.setOrigin(origin)
+
+    return value, nil
+}
@@ -0,0 +1,250 @@
+package parse
+
+import (
+    "testing"
+    "errors"
+    "strings"
+    "reflect"
+    "net"
+
+    "net/http"
+)
+
+func TestFromRequestQueryE_EmptyPolicy(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com?flag=&bare", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    // Empty values are missing by default.
+
+    if value, err := FromRequestQueryE(req, "flag", "string", false); err != nil || value != nil {
+        t.Fatalf("Expected empty value to be absent: [%v] [%v]", value, err)
+    } else if _, err := FromRequestQueryE(req, "flag", "string", true); errors.Is(err, ErrMissing) == false {
+        t.Fatalf("Expected missing error: [%v]", err)
+    }
+
+    for _, name := range []string { "flag", "bare" } {
+        value, err := FromRequestQueryE(req, name, "string", true, WithEmptyPolicy(EmptyAsValue))
+        if err != nil {
+            t.Fatalf("Empty value [%s] not accepted: [%s]", name, err)
+        } else if value != "" {
+            t.Fatalf("Empty value [%s] not correct: [%v]", name, value)
+        }
+    }
+
+    _, err = FromRequestQueryE(req, "flag", "uint64", true, WithEmptyPolicy(EmptyAsValue))
+    if errors.Is(err, ErrSyntax) == false {
+        t.Fatalf("Expected syntax error: [%v]", err)
+    }
+
+    value, err := FromRequestQueryE(req, "flag", "uint64", true, WithEmptyPolicy(EmptyAsZero))
+    if err != nil {
+        t.Fatalf("Empty value not accepted: [%s]", err)
+    } else if value != uint64(0) {
+        t.Fatalf("Zero-value not correct: [%v]", value)
+    }
+
+    // An absent value is missing regardless of the policy.
+
+    for _, ep := range []EmptyPolicy { EmptyAsMissing, EmptyAsValue, EmptyAsZero } {
+        if value, err := FromRequestQueryE(req, "other", "uint64", false, WithEmptyPolicy(ep)); err != nil || value != nil {
+            t.Fatalf("Expected absent value with policy [%s]: [%v] [%v]", ep, value, err)
+        } else if _, err := FromRequestQueryE(req, "other", "uint64", true, WithEmptyPolicy(ep)); errors.Is(err, ErrMissing) == false {
+            t.Fatalf("Expected missing error with policy [%s]: [%v]", ep, err)
+        }
+    }
+}
+
+func TestFromRequestBodyE_EmptyPolicy(t *testing.T) {
+    req, err := http.NewRequest("POST", "http://example.com", strings.NewReader("flag="))
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+    if value, err := FromRequestBodyE(req, "flag", "string", true, WithEmptyPolicy(EmptyAsValue)); err != nil || value != "" {
+        t.Fatalf("Empty value not correct: [%v] [%v]", value, err)
+    } else if value, err := FromRequestBodyE(req, "other", "string", false, WithEmptyPolicy(EmptyAsValue)); err != nil || value != nil {
+        t.Fatalf("Expected absent value: [%v] [%v]", value, err)
+    }
+}
+
+func TestFromRequestHeaderE_EmptyPolicy(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    req.Header.Set("X-Flag", "")
+
+    if value, err := FromRequestHeaderE(req, "X-Flag", "bool", false); err != nil || value != nil {
+        t.Fatalf("Expected empty value to be absent: [%v] [%v]", value, err)
+    } else if value, err := FromRequestHeaderE(req, "X-Flag", "bool", true, WithEmptyPolicy(EmptyAsZero)); err != nil || value != false {
+        t.Fatalf("Zero-value not correct: [%v] [%v]", value, err)
+    } else if value, err := FromRequestHeaderE(req, "X-Other", "bool", false, WithEmptyPolicy(EmptyAsZero)); err != nil || value != nil {
+        t.Fatalf("Expected absent value: [%v] [%v]", value, err)
+    }
+}
+
+func TestFromRequestCookieE_EmptyPolicy(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    req.AddCookie(&http.Cookie{ Name: "session", Value: "" })
+
+    if value, err := FromRequestCookieE(req, "session", "string", true, WithEmptyPolicy(EmptyAsValue)); err != nil || value != "" {
+        t.Fatalf("Empty value not correct: [%v] [%v]", value, err)
+    }
+}
+
+func TestFromEnvironE_EmptyPolicy(t *testing.T) {
+    t.Setenv("MULTIPARSE_TEST_EMPTY", "")
+
+    if value, err := FromEnvironE("MULTIPARSE_TEST_EMPTY", "string", false); err != nil || value != nil {
+        t.Fatalf("Expected empty value to be absent: [%v] [%v]", value, err)
+    } else if value, err := FromEnvironE("MULTIPARSE_TEST_EMPTY", "string", true, WithEmptyPolicy(EmptyAsValue)); err != nil || value != "" {
+        t.Fatalf("Empty value not correct: [%v] [%v]", value, err)
+    } else if value, err := FromEnvironE("MULTIPARSE_TEST_UNSET", "string", false, WithEmptyPolicy(EmptyAsValue)); err != nil || value != nil {
+        t.Fatalf("Expected unset variable to be absent: [%v] [%v]", value, err)
+    }
+}
+
+func TestFromMapE_EmptyPolicy(t *testing.T) {
+    dict := map[string]string {
+        "empty": "",
+    }
+
+    if value, err := FromMapE(dict, "empty", "int32", true, WithEmptyPolicy(EmptyAsZero)); err != nil || value != int32(0) {
+        t.Fatalf("Zero-value not correct: [%v] [%v]", value, err)
+    } else if _, err := FromMapE(dict, "absent", "int32", true, WithEmptyPolicy(EmptyAsZero)); errors.Is(err, ErrMissing) == false {
+        t.Fatalf("Expected missing error: [%v]", err)
+    }
+}
+
+func TestFromInterfaceMapE_EmptyPolicy(t *testing.T) {
+    dict := map[string]interface{} {
+        "null": nil,
+    }
+
+    if value, err := FromInterfaceMapE(dict, "null", "float64", false); err != nil || value != nil {
+        t.Fatalf("Expected null to be absent: [%v] [%v]", value, err)
+    } else if value, err := FromInterfaceMapE(dict, "null", "float64", true, WithEmptyPolicy(EmptyAsValue)); err != nil || value != float64(0) {
+        t.Fatalf("Null value not correct: [%v] [%v]", value, err)
+    }
+}
+
+func TestFromInterfaceMapE_TypeMismatch(t *testing.T) {
+    dict := map[string]interface{} {
+        "list": []interface{} { 1 },
+        "object": map[string]interface{} { "a": 1 },
+    }
+
+    _, err := FromInterfaceMapE(dict, "list", "int64", true)
+    if errors.Is(err, ErrSyntax) == false || IsClientError(err) == false {
+        t.Fatalf("Expected syntax error for list: [%v]", err)
+    } else if errors.Is(err, ErrUnsupportedKind) == true {
+        t.Fatalf("Type mismatch reported as unsupported kind: [%v]", err)
+    }
+
+    _, err = FromInterfaceMapE(dict, "object", "rfc3339", true)
+    if errors.Is(err, ErrSyntax) == false {
+        t.Fatalf("Expected syntax error for object: [%v]", err)
+    }
+
+    // An unknown kind is still a programming error.
+
+    _, err = FromInterfaceMapE(dict, "list", "invalid-kind", true)
+    if errors.Is(err, ErrUnsupportedKind) == false {
+        t.Fatalf("Expected unsupported-kind error: [%v]", err)
+    }
+}
+
+func TestQueryE_EmptyPolicy(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com?name=", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    if _, err := QueryE[string](req, "name", true); errors.Is(err, ErrMissing) == false {
+        t.Fatalf("Expected missing error: [%v]", err)
+    } else if value, err := QueryE[string](req, "name", true, WithEmptyPolicy(EmptyAsValue)); err != nil || value != "" {
+        t.Fatalf("Empty value not correct: [%v] [%v]", value, err)
+    }
+}
+
+func TestEmptyPolicy_String(t *testing.T) {
+    if s := EmptyAsZero.String(); s != "EmptyAsZero" {
+        t.Fatalf("Name not correct: [%s]", s)
+    } else if s := EmptyPolicy(99).String(); s != "EmptyPolicy(99)" {
+        t.Fatalf("Name of unknown policy not correct: [%s]", s)
+    }
+}
+
+func TestWithDefault(t *testing.T) {
+    limitDefault := WithDefault("uint32", 50)
+
+    req, err := http.NewRequest("GET", "http://example.com?offset=10&limit=", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    if value := FromRequestQuery(req, "limit", "uint32", false, limitDefault); value != uint32(50) {
+        t.Fatalf("Default not correct: [%v]", value)
+    } else if value := FromRequestQuery(req, "offset", "uint32", false, limitDefault); value != uint32(10) {
+        t.Fatalf("Present value not correct: [%v]", value)
+    } else if value := FromRequestQuery(req, "limit", "uint32", false, limitDefault, WithEmptyPolicy(EmptyAsZero)); value != uint32(0) {
+        t.Fatalf("Empty value should not use the default: [%v]", value)
+    }
+
+    if _, err := FromRequestQueryE(req, "limit", "uint32", true, limitDefault); errors.Is(err, ErrMissing) == false {
+        t.Fatalf("Expected missing error for required value: [%v]", err)
+    }
+
+    _, err = FromRequestQueryE(req, "limit", "uint64", false, limitDefault)
+    if errors.Is(err, ErrUnsupportedKind) == false {
+        t.Fatalf("Expected unsupported-kind error for mismatched default: [%v]", err)
+    }
+
+    if value, err := QueryE[uint32](req, "limit", false, limitDefault); err != nil || value != 50 {
+        t.Fatalf("Typed default not correct: [%v] [%v]", value, err)
+    }
+}
+
+func TestWithDefault_NotShared(t *testing.T) {
+    AddTextKind("default-ip", reflect.TypeOf(net.IP{}))
+
+    addressDefault := WithDefault("default-ip", "10.0.0.1")
+
+    req, err := http.NewRequest("GET", "http://example.com", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    first := FromRequestQuery(req, "address", "default-ip", false, addressDefault).(net.IP)
+    first[len(first) - 1] = 99
+
+    second := FromRequestQuery(req, "address", "default-ip", false, addressDefault).(net.IP)
+    if second.Equal(net.IPv4(10, 0, 0, 1)) == false {
+        t.Fatalf("Default was shared between calls: [%s]", second)
+    }
+}
+
+func TestWithDefaultE_Invalid(t *testing.T) {
+    if _, err := WithDefaultE("uint8", "300"); errors.Is(err, ErrRange) == false {
+        t.Fatalf("Expected range error: [%v]", err)
+    } else if _, err := WithDefaultE("uint8", nil); err == nil {
+        t.Fatalf("Expected error for nil default.")
+    }
+
+    defer func() {
+        if errRaw := recover(); errRaw == nil {
+            t.Fatalf("Expected panic for invalid default.")
+        }
+    }()
+
+    WithDefault("bool", "maybe")
+}
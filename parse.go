@@ -1,11 +1,9 @@
 package parse
 
 import (
-    "os"
-    "fmt"
     "reflect"
     "time"
-    "strings"
+    "mime"
 
     "net/http"
     "encoding/json"
@@ -65,17 +63,15 @@ var (
         "uint16": reflect.TypeOf(uint16(0)),
         "uint32": reflect.TypeOf(uint32(0)),
         "uint64": reflect.TypeOf(uint64(0)),
-        "hex8": stringType,
-        "hex16": stringType,
-        "hex32": stringType,
-        "hex64": stringType,
+        "hex8": reflect.TypeOf(uint8(0)),
+        "hex16": reflect.TypeOf(uint16(0)),
+        "hex32": reflect.TypeOf(uint32(0)),
+        "hex64": reflect.TypeOf(uint64(0)),
         "float32": reflect.TypeOf(float32(0)),
         "float64": reflect.TypeOf(float64(0)),
         "bool": reflect.TypeOf(true),
         "rfc3339": reflect.TypeOf(t),
     }
-
-    parsers = map[string]Parser {}
 )
 
 type Parser interface {
@@ -104,88 +100,123 @@ type Parser interface {
     Rfc3339(value interface{}) time.Time
 }
 
-func AddParser(fromType reflect.Type, p Parser) {
-    parsers[fromType.Name()] = p
-}
+// ParserE is the error-returning counterpart of Parser. It is the
+// combination of every per-kind capability (e.g. Int64KindParser). ParseE
+// prefers these methods when a parser provides them. Parsers that only
+// implement Parser still work; their panics are recovered and returned as
+// errors. Runtime errors (e.g. a nil dereference) are bugs and are
+// re-raised.
+type ParserE interface {
+    StringKindParser
 
-func GetParser(fromType reflect.Type) Parser {
-    p, found := parsers[fromType.Name()]
-    if found == false {
-        log.Panic(fmt.Errorf("no parser registered for type [%s]", fromType))
-    }
+    Int8KindParser
+    Int16KindParser
+    Int32KindParser
+    Int64KindParser
+
+    Uint8KindParser
+    Uint16KindParser
+    Uint32KindParser
+    Uint64KindParser
 
-    return p
+    Hex8KindParser
+    Hex16KindParser
+    Hex32KindParser
+    Hex64KindParser
+
+    Float32KindParser
+    Float64KindParser
+
+    BoolKindParser
+
+    Rfc3339KindParser
 }
 
-func Parse(valueRaw interface{}, toKindName string) interface{} {
-    if valueRaw == nil {
-        if t, found := KindNameZeroType[toKindName]; found == false {
-            log.Panic(fmt.Errorf("kind [%s] does not have a zero-type defined", toKindName))
-        } else {
-            return reflect.Zero(t)
-        }
-    }
+// AddParser registers a parser with the default registry. See
+// Registry.AddParser().
+func AddParser(fromType reflect.Type, p interface{}) {
+    DefaultRegistry.AddParser(fromType, p)
+}
 
-    fromType := reflect.TypeOf(valueRaw)
-    p := GetParser(fromType)
+// GetParser returns the parser for the given type from the default registry.
+// See Registry.GetParser().
+func GetParser(fromType reflect.Type) Parser {
+    return DefaultRegistry.GetParser(fromType)
+}
 
-    mn, found := NameMethodMap[toKindName]
-    if found == false {
-        log.Panic(fmt.Errorf("no operation from type [%s] to kind [%s]", fromType, toKindName))
-    }
+// Parse parses the given value to the given kind using the default
+// registry. It panics on failure.
+func Parse(valueRaw interface{}, toKindName string) interface{} {
+    return DefaultRegistry.Parse(valueRaw, toKindName)
+}
 
-    pValue := reflect.ValueOf(p)
+// ParseE parses the given value to the given kind using the default
+// registry. Failures are returned as a *ParseError rather than panicking.
+func ParseE(valueRaw interface{}, toKindName string) (value interface{}, err error) {
+    return DefaultRegistry.ParseE(valueRaw, toKindName)
+}
 
-    m := pValue.MethodByName(mn)
-    if m.IsValid() == false {
-        log.Panic(fmt.Errorf("parser [%s] method [%s] not valid", pValue.Type(), mn))
-    }
+// ParseOr parses the given value to the given kind using the default
+// registry, producing the default for a nil value. See Registry.ParseOr().
+func ParseOr(valueRaw interface{}, toKindName string, defaultValue interface{}) (value interface{}, present bool) {
+    return DefaultRegistry.ParseOr(valueRaw, toKindName, defaultValue)
+}
 
-    vV := reflect.ValueOf(valueRaw)
-    parsed := m.Call([]reflect.Value { vV })
-    return parsed[0].Interface()
+// ParseOrE is the error-returning variant of ParseOr.
+func ParseOrE(valueRaw interface{}, toKindName string, defaultValue interface{}) (value interface{}, present bool, err error) {
+    return DefaultRegistry.ParseOrE(valueRaw, toKindName, defaultValue)
 }
 
 // FromRequestBody parses values from a form-encoded HTTP request's body.
-func FromRequestBody(r *http.Request, name string, kindName string, required bool) (value interface{}) {
-    valueRaw := r.FormValue(name)
-    if valueRaw == "" {
-        if required == true {
-            log.Panic(fmt.Errorf("regular body argument empty or omitted: [%s]", name))
-        } else {
-            return nil
-        }
-    }
+func FromRequestBody(r *http.Request, name string, kindName string, required bool, opts ...Option) (value interface{}) {
+    value, err := FromRequestBodyE(r, name, kindName, required, opts...)
+    log.PanicIf(err)
+
+    return value
+}
 
-    return Parse(valueRaw, kindName)
+// FromRequestBodyE is the error-returning variant of FromRequestBody.
+func FromRequestBodyE(r *http.Request, name string, kindName string, required bool, opts ...Option) (value interface{}, err error) {
+    return GetE(NewBodySource(r), name, kindName, required, opts...)
 }
 
 // FromRequestQuery parses values from an HTTP request's query.
-func FromRequestQuery(r *http.Request, name string, kindName string, required bool) (value interface{}) {
-    valueRaw := r.URL.Query().Get(name)
-    if valueRaw == "" {
-        if required == true {
-            log.Panic(fmt.Errorf("query argument empty or omitted: [%s]", name))
-        } else {
-            return nil
-        }
-    }
+func FromRequestQuery(r *http.Request, name string, kindName string, required bool, opts ...Option) (value interface{}) {
+    value, err := FromRequestQueryE(r, name, kindName, required, opts...)
+    log.PanicIf(err)
 
-    return Parse(valueRaw, kindName)
+    return value
+}
+
+// FromRequestQueryE is the error-returning variant of FromRequestQuery.
+func FromRequestQueryE(r *http.Request, name string, kindName string, required bool, opts ...Option) (value interface{}, err error) {
+    return GetE(NewQuerySource(r), name, kindName, required, opts...)
 }
 
 // FromRequestHeader parses values from an HTTP request's headers.
-func FromRequestHeader(r *http.Request, name string, kindName string, required bool) (value interface{}) {
-    valueRaw := r.Header.Get(name)
-    if valueRaw == "" {
-        if required == true {
-            log.Panic(fmt.Errorf("HTTP header empty or omitted: [%s]", name))
-        } else {
-            return nil
-        }
-    }
+func FromRequestHeader(r *http.Request, name string, kindName string, required bool, opts ...Option) (value interface{}) {
+    value, err := FromRequestHeaderE(r, name, kindName, required, opts...)
+    log.PanicIf(err)
 
-    return Parse(valueRaw, kindName)
+    return value
+}
+
+// FromRequestHeaderE is the error-returning variant of FromRequestHeader.
+func FromRequestHeaderE(r *http.Request, name string, kindName string, required bool, opts ...Option) (value interface{}, err error) {
+    return GetE(NewHeaderSource(r), name, kindName, required, opts...)
+}
+
+// FromRequestCookie parses values from an HTTP request's cookies.
+func FromRequestCookie(r *http.Request, name string, kindName string, required bool, opts ...Option) (value interface{}) {
+    value, err := FromRequestCookieE(r, name, kindName, required, opts...)
+    log.PanicIf(err)
+
+    return value
+}
+
+// FromRequestCookieE is the error-returning variant of FromRequestCookie.
+func FromRequestCookieE(r *http.Request, name string, kindName string, required bool, opts ...Option) (value interface{}, err error) {
+    return GetE(NewCookieSource(r), name, kindName, required, opts...)
 }
 
 type JsonRequestParser struct {
@@ -193,80 +224,110 @@ type JsonRequestParser struct {
 }
 
 func NewJsonRequestParser(r *http.Request) *JsonRequestParser {
+    jrp, err := NewJsonRequestParserE(r)
+    log.PanicIf(err)
+
+    return jrp
+}
+
+// NewJsonRequestParserE is the error-returning variant of
+// NewJsonRequestParser. An unsupported content-type or an undecodable body
+// is returned as a *ParseError.
+func NewJsonRequestParserE(r *http.Request) (jrp *JsonRequestParser, err error) {
     d := map[string]interface{} {}
 
+    // Parameters (e.g. "charset=utf-8") are allowed but ignored.
     ct := r.Header.Get("Content-Type")
-    ct = strings.ToLower(ct)
-    if ct != "" && ct != "application/json" {
-        log.Panicf("content-type not supported")
+    if ct != "" {
+        mediaType, _, err := mime.ParseMediaType(ct)
+        if err != nil {
+            pe := newParseError("", ct, ErrSyntax, err, "")
+            return nil, pe.withField(SourceHeader, "Content-Type")
+        } else if mediaType != "application/json" {
+            pe := newParseError("", ct, ErrSyntax, nil, "content-type not supported")
+            return nil, pe.withField(SourceHeader, "Content-Type")
+        }
     }
 
     j := json.NewDecoder(r.Body)
     defer r.Body.Close()
 
-    err := j.Decode(&d)
-    log.PanicIf(err)
+    // Keep numbers as json.Number so that large integers do not lose
+    // precision by being decoded as a float64.
+    j.UseNumber()
 
-    jrp := &JsonRequestParser{
+    err = j.Decode(&d)
+    if err != nil {
+        pe := newParseError("", nil, ErrSyntax, err, "")
+        return nil, pe.withField(SourceJson, "")
+    }
+
+    jrp = &JsonRequestParser{
         data: d,
     }
 
-    return jrp
+    return jrp, nil
+}
+
+// Lookup returns the decoded value for the name. This makes the parser a
+// Source.
+func (jrp *JsonRequestParser) Lookup(name string) (valueRaw interface{}, present bool) {
+    valueRaw, present = jrp.data[name]
+    return valueRaw, present
+}
+
+func (jrp *JsonRequestParser) Describe() string {
+    return SourceJson
 }
 
 // Get parses values from an HTTP request.
-func (jrp *JsonRequestParser) Get(name string, kindName string, required bool) (value interface{}) {
-    valueRaw := jrp.data[name]
-    if valueRaw == "" {
-        if required == true {
-            log.Panic(fmt.Errorf("JSON body argument empty or omitted: [%s]", name))
-        } else {
-            return nil
-        }
-    }
+func (jrp *JsonRequestParser) Get(name string, kindName string, required bool, opts ...Option) (value interface{}) {
+    value, err := jrp.GetE(name, kindName, required, opts...)
+    log.PanicIf(err)
+
+    return value
+}
 
-    return Parse(valueRaw, kindName)
+// GetE is the error-returning variant of Get.
+func (jrp *JsonRequestParser) GetE(name string, kindName string, required bool, opts ...Option) (value interface{}, err error) {
+    return GetE(jrp, name, kindName, required, opts...)
 }
 
 // FromMap parses values from a map.
-func FromMap(dict map[string]string, name string, kindName string, required bool) (value interface{}) {
-    valueRaw := dict[name]
-    if valueRaw == "" {
-        if required == true {
-            log.Panic(fmt.Errorf("map key empty or absent: [%s]", name))
-        } else {
-            return nil
-        }
-    }
+func FromMap(dict map[string]string, name string, kindName string, required bool, opts ...Option) (value interface{}) {
+    value, err := FromMapE(dict, name, kindName, required, opts...)
+    log.PanicIf(err)
+
+    return value
+}
 
-    return Parse(valueRaw, kindName)
+// FromMapE is the error-returning variant of FromMap.
+func FromMapE(dict map[string]string, name string, kindName string, required bool, opts ...Option) (value interface{}, err error) {
+    return GetE(NewMapSource(dict), name, kindName, required, opts...)
 }
 
 // FromInterfaceMap parses values from a map.
-func FromInterfaceMap(dict map[string]interface{}, name string, kindName string, required bool) (value interface{}) {
-    valueRaw := dict[name]
-    valueString := valueRaw.(string)
-    if valueString == "" {
-        if required == true {
-            log.Panic(fmt.Errorf("map key empty or absent: [%s]", name))
-        } else {
-            return nil
-        }
-    }
+func FromInterfaceMap(dict map[string]interface{}, name string, kindName string, required bool, opts ...Option) (value interface{}) {
+    value, err := FromInterfaceMapE(dict, name, kindName, required, opts...)
+    log.PanicIf(err)
 
-    return Parse(valueString, kindName)
+    return value
+}
+
+// FromInterfaceMapE is the error-returning variant of FromInterfaceMap.
+func FromInterfaceMapE(dict map[string]interface{}, name string, kindName string, required bool, opts ...Option) (value interface{}, err error) {
+    return GetE(NewInterfaceMapSource(dict), name, kindName, required, opts...)
 }
 
 // FromEnviron parses values from the environment
-func FromEnviron(name string, kindName string, required bool) (value interface{}) {
-    valueRaw := os.Getenv(name)
-    if valueRaw == "" {
-        if required == true {
-            log.Panic(fmt.Errorf("environment argument empty or omitted: [%s]", name))
-        } else {
-            return nil
-        }
-    }
+func FromEnviron(name string, kindName string, required bool, opts ...Option) (value interface{}) {
+    value, err := FromEnvironE(name, kindName, required, opts...)
+    log.PanicIf(err)
+
+    return value
+}
 
-    return Parse(valueRaw, kindName)
+// FromEnvironE is the error-returning variant of FromEnviron.
+func FromEnvironE(name string, kindName string, required bool, opts ...Option) (value interface{}, err error) {
+    return GetE(NewEnvironSource(), name, kindName, required, opts...)
 }
@@ -3,6 +3,9 @@ package parse
 import (
     "testing"
     "os"
+    "errors"
+    "reflect"
+    "strings"
 
     "net/http"
     "net/url"
@@ -230,3 +233,167 @@ func TestFromEnviron_Uint64(t *testing.T) {
                  recovered, value)
     }
 }
+
+func TestParseE(t *testing.T) {
+    value, err := ParseE("123", "uint64")
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if value != uint64(123) {
+        t.Fatalf("Parsed value not correct: [%v]", value)
+    }
+}
+
+func TestParseE_SyntaxError(t *testing.T) {
+    _, err := ParseE("abc", "uint64")
+    if err == nil {
+        t.Fatalf("Expected error for invalid value.")
+    }
+
+    pe, ok := err.(*ParseError)
+    if ok == false {
+        t.Fatalf("Error is not a ParseError: [%v]", err)
+    } else if pe.Value() != "abc" {
+        t.Fatalf("ParseError value not correct: [%v]", pe.Value())
+    }
+}
+
+func TestParseE_UnknownKind(t *testing.T) {
+    _, err := ParseE("123", "invalid-kind")
+    if err == nil {
+        t.Fatalf("Expected error for invalid kind.")
+    } else if err.Error() != "no operation from type [string] to kind [invalid-kind]" {
+        t.Fatalf("Error not correct: [%s]", err)
+    }
+}
+
+func TestFromRequestQueryE_Required_Miss(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    _, err = FromRequestQueryE(req, "aa", "uint64", true)
+    if err == nil {
+        t.Fatalf("Expected error for missing but required argument.")
+    } else if err.Error() != "query argument empty or omitted: [aa]" {
+        t.Fatalf("Error not correct: [%s]", err)
+    }
+}
+
+func TestFromRequestQueryE_Invalid(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com?aa=abc", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    _, err = FromRequestQueryE(req, "aa", "uint64", true)
+    if _, ok := err.(*ParseError); ok == false {
+        t.Fatalf("Expected ParseError for invalid argument: [%v]", err)
+    }
+}
+
+func TestJsonRequestParser_GetE(t *testing.T) {
+    req, err := http.NewRequest("POST", "http://example.com", strings.NewReader(`{"aa": "123"}`))
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    jrp, err := NewJsonRequestParserE(req)
+    if err != nil {
+        t.Fatalf("Could not create JSON parser: [%s]", err)
+    }
+
+    value, err := jrp.GetE("aa", "uint64", true)
+    if err != nil {
+        t.Fatalf("Could not parse JSON value: [%s]", err)
+    } else if value != uint64(123) {
+        t.Fatalf("Parsed value not correct: [%v]", value)
+    }
+
+    _, err = jrp.GetE("bb", "uint64", true)
+    if err == nil {
+        t.Fatalf("Expected error for missing but required argument.")
+    }
+}
+
+func TestNewJsonRequestParserE_ContentType(t *testing.T) {
+    for _, ct := range []string { "application/json", "Application/JSON; charset=utf-8" } {
+        req, err := http.NewRequest("POST", "http://example.com", strings.NewReader(`{"aa": "123"}`))
+        if err != nil {
+            t.Fatalf("Could not fabricate request: [%s]", err)
+        }
+
+        req.Header.Set("Content-Type", ct)
+
+        _, err = NewJsonRequestParserE(req)
+        if err != nil {
+            t.Fatalf("Content-type [%s] not accepted: [%s]", ct, err)
+        }
+    }
+
+    for _, ct := range []string { "text/plain", "application/json-seq", "application/json; charset" } {
+        req, err := http.NewRequest("POST", "http://example.com", strings.NewReader(`{"aa": "123"}`))
+        if err != nil {
+            t.Fatalf("Could not fabricate request: [%s]", err)
+        }
+
+        req.Header.Set("Content-Type", ct)
+
+        _, err = NewJsonRequestParserE(req)
+        if errors.Is(err, ErrSyntax) == false {
+            t.Fatalf("Expected syntax error for content-type [%s]: [%v]", ct, err)
+        }
+    }
+}
+
+func TestNewJsonRequestParserE_Invalid(t *testing.T) {
+    req, err := http.NewRequest("POST", "http://example.com", strings.NewReader(`{`))
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    _, err = NewJsonRequestParserE(req)
+    if _, ok := err.(*ParseError); ok == false {
+        t.Fatalf("Expected ParseError for invalid body: [%v]", err)
+    }
+}
+
+func TestParseE_Pointer(t *testing.T) {
+    s := "123"
+
+    value, err := ParseE(&s, "uint64")
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if value != uint64(123) {
+        t.Fatalf("Parsed value not correct: [%v]", value)
+    }
+
+    sp := &s
+    value, err = ParseE(&sp, "uint64")
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if value != uint64(123) {
+        t.Fatalf("Parsed value not correct: [%v]", value)
+    }
+
+    p := GetParser(reflect.TypeOf(&s))
+    if p.Uint64(s) != 123 {
+        t.Fatalf("Parser for pointer type not correct.")
+    }
+}
+
+func TestParseE_NilPointer(t *testing.T) {
+    var s *string
+
+    _, err := ParseE(s, "uint64")
+    if err != nil {
+        t.Fatalf("Nil pointer should be treated as nil: [%s]", err)
+    }
+
+    var unsupported *struct{}
+
+    _, err = ParseE(unsupported, "uint64")
+    if errors.Is(err, ErrUnsupportedKind) == false {
+        t.Fatalf("Expected unsupported-kind error: [%v]", err)
+    }
+}
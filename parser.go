@@ -7,6 +7,8 @@ import (
     "github.com/dsoprea/go-logging"
 )
 
+// StringParser parses string values. Every kind has an error-returning
+// method (e.g. Int64E) and a panicking counterpart (e.g. Int64).
 type StringParser struct {
 }
 
@@ -14,156 +16,307 @@ func NewStringParser() Parser {
     return new(StringParser)
 }
 
-func (sp StringParser) String(value interface{}) string {
+func (sp StringParser) StringE(value interface{}) (string, error) {
     s := value.(string)
+    return s, nil
+}
+
+func (sp StringParser) String(value interface{}) string {
+    s, err := sp.StringE(value)
+    log.PanicIf(err)
+
     return s
 }
 
-func (sp StringParser) Int8(value interface{}) int8 {
+func (sp StringParser) Int8E(value interface{}) (int8, error) {
     s := value.(string)
 
     p, err := strconv.ParseInt(s, 10, 8)
+    if err != nil {
+        return 0, err
+    }
+
+    return int8(p), nil
+}
+
+func (sp StringParser) Int8(value interface{}) int8 {
+    p, err := sp.Int8E(value)
     log.PanicIf(err)
 
-    return int8(p)
+    return p
 }
 
-func (sp StringParser) Int16(value interface{}) int16 {
+func (sp StringParser) Int16E(value interface{}) (int16, error) {
     s := value.(string)
 
     p, err := strconv.ParseInt(s, 10, 16)
+    if err != nil {
+        return 0, err
+    }
+
+    return int16(p), nil
+}
+
+func (sp StringParser) Int16(value interface{}) int16 {
+    p, err := sp.Int16E(value)
     log.PanicIf(err)
 
-    return int16(p)
+    return p
 }
 
-func (sp StringParser) Int32(value interface{}) int32 {
+func (sp StringParser) Int32E(value interface{}) (int32, error) {
     s := value.(string)
 
     p, err := strconv.ParseInt(s, 10, 32)
+    if err != nil {
+        return 0, err
+    }
+
+    return int32(p), nil
+}
+
+func (sp StringParser) Int32(value interface{}) int32 {
+    p, err := sp.Int32E(value)
     log.PanicIf(err)
-    
-    return int32(p)
+
+    return p
 }
 
-func (sp StringParser) Int64(value interface{}) int64 {
+func (sp StringParser) Int64E(value interface{}) (int64, error) {
     s := value.(string)
 
     p, err := strconv.ParseInt(s, 10, 64)
+    if err != nil {
+        return 0, err
+    }
+
+    return int64(p), nil
+}
+
+func (sp StringParser) Int64(value interface{}) int64 {
+    p, err := sp.Int64E(value)
     log.PanicIf(err)
-    
-    return int64(p)
+
+    return p
 }
 
-func (sp StringParser) Uint8(value interface{}) uint8 {
+func (sp StringParser) Uint8E(value interface{}) (uint8, error) {
     s := value.(string)
-    
+
     p, err := strconv.ParseUint(s, 10, 8)
+    if err != nil {
+        return 0, err
+    }
+
+    return uint8(p), nil
+}
+
+func (sp StringParser) Uint8(value interface{}) uint8 {
+    p, err := sp.Uint8E(value)
     log.PanicIf(err)
-    
-    return uint8(p)
+
+    return p
 }
 
-func (sp StringParser) Uint16(value interface{}) uint16 {
+func (sp StringParser) Uint16E(value interface{}) (uint16, error) {
     s := value.(string)
 
     p, err := strconv.ParseUint(s, 10, 16)
+    if err != nil {
+        return 0, err
+    }
+
+    return uint16(p), nil
+}
+
+func (sp StringParser) Uint16(value interface{}) uint16 {
+    p, err := sp.Uint16E(value)
     log.PanicIf(err)
 
-    return uint16(p)
+    return p
 }
 
-func (sp StringParser) Uint32(value interface{}) uint32 {
+func (sp StringParser) Uint32E(value interface{}) (uint32, error) {
     s := value.(string)
 
     p, err := strconv.ParseUint(s, 10, 32)
+    if err != nil {
+        return 0, err
+    }
+
+    return uint32(p), nil
+}
+
+func (sp StringParser) Uint32(value interface{}) uint32 {
+    p, err := sp.Uint32E(value)
     log.PanicIf(err)
 
-    return uint32(p)
+    return p
 }
 
-func (sp StringParser) Uint64(value interface{}) uint64 {
+func (sp StringParser) Uint64E(value interface{}) (uint64, error) {
     s := value.(string)
 
     p, err := strconv.ParseUint(s, 10, 64)
+    if err != nil {
+        return 0, err
+    }
+
+    return p, nil
+}
+
+func (sp StringParser) Uint64(value interface{}) uint64 {
+    p, err := sp.Uint64E(value)
     log.PanicIf(err)
-    
+
     return p
 }
 
-func (sp StringParser) Hex8(value interface{}) uint8 {
+func (sp StringParser) Hex8E(value interface{}) (uint8, error) {
     s := value.(string)
 
     p, err := strconv.ParseUint(s, 16, 8)
+    if err != nil {
+        return 0, err
+    }
+
+    return uint8(p), nil
+}
+
+func (sp StringParser) Hex8(value interface{}) uint8 {
+    p, err := sp.Hex8E(value)
     log.PanicIf(err)
-    
-    return uint8(p)
+
+    return p
 }
 
-func (sp StringParser) Hex16(value interface{}) uint16 {
+func (sp StringParser) Hex16E(value interface{}) (uint16, error) {
     s := value.(string)
 
     p, err := strconv.ParseUint(s, 16, 16)
+    if err != nil {
+        return 0, err
+    }
+
+    return uint16(p), nil
+}
+
+func (sp StringParser) Hex16(value interface{}) uint16 {
+    p, err := sp.Hex16E(value)
     log.PanicIf(err)
-    
-    return uint16(p)
+
+    return p
 }
 
-func (sp StringParser) Hex32(value interface{}) uint32 {
+func (sp StringParser) Hex32E(value interface{}) (uint32, error) {
     s := value.(string)
 
     p, err := strconv.ParseUint(s, 16, 32)
+    if err != nil {
+        return 0, err
+    }
+
+    return uint32(p), nil
+}
+
+func (sp StringParser) Hex32(value interface{}) uint32 {
+    p, err := sp.Hex32E(value)
     log.PanicIf(err)
 
-    return uint32(p)
+    return p
 }
 
-func (sp StringParser) Hex64(value interface{}) uint64 {
+func (sp StringParser) Hex64E(value interface{}) (uint64, error) {
     s := value.(string)
-    
+
     p, err := strconv.ParseUint(s, 16, 64)
+    if err != nil {
+        return 0, err
+    }
+
+    return p, nil
+}
+
+func (sp StringParser) Hex64(value interface{}) uint64 {
+    p, err := sp.Hex64E(value)
     log.PanicIf(err)
-    
+
     return p
 }
 
-func (sp StringParser) Float32(value interface{}) float32 {
+func (sp StringParser) Float32E(value interface{}) (float32, error) {
     s := value.(string)
 
     p, err := strconv.ParseFloat(s, 32)
+    if err != nil {
+        return 0, err
+    }
+
+    return float32(p), nil
+}
+
+func (sp StringParser) Float32(value interface{}) float32 {
+    p, err := sp.Float32E(value)
     log.PanicIf(err)
-    
-    return float32(p)
+
+    return p
 }
 
-func (sp StringParser) Float64(value interface{}) float64 {
+func (sp StringParser) Float64E(value interface{}) (float64, error) {
     s := value.(string)
-    
+
     p, err := strconv.ParseFloat(s, 64)
+    if err != nil {
+        return 0, err
+    }
+
+    return p, nil
+}
+
+func (sp StringParser) Float64(value interface{}) float64 {
+    p, err := sp.Float64E(value)
     log.PanicIf(err)
-    
+
     return p
 }
 
-func (sp StringParser) Bool(value interface{}) bool {
+func (sp StringParser) BoolE(value interface{}) (bool, error) {
     s := value.(string)
 
     p, err := strconv.ParseBool(s)
+    if err != nil {
+        return false, err
+    }
+
+    return p, nil
+}
+
+func (sp StringParser) Bool(value interface{}) bool {
+    p, err := sp.BoolE(value)
     log.PanicIf(err)
-    
+
     return p
 }
 
-func (sp StringParser) Rfc3339(value interface{}) time.Time {
+func (sp StringParser) Rfc3339E(value interface{}) (time.Time, error) {
     s := value.(string)
 
     t, err := time.Parse(time.RFC3339, s)
+    if err != nil {
+        return time.Time{}, err
+    }
+
+    return t, nil
+}
+
+func (sp StringParser) Rfc3339(value interface{}) time.Time {
+    t, err := sp.Rfc3339E(value)
     log.PanicIf(err)
-    
+
     return t
 }
 
 func init() {
     p := NewStringParser()
-    AddParser(stringType, p)
+    addBuiltinParser(stringType, p)
 }
@@ -0,0 +1,254 @@
+package parse
+
+import (
+    "reflect"
+    "strconv"
+
+    "github.com/dsoprea/go-logging"
+)
+
+// BoolParser parses bool values (e.g. JSON booleans). The numeric kinds
+// produce 0 or 1.
+type BoolParser struct {
+}
+
+func NewBoolParser() *BoolParser {
+    return new(BoolParser)
+}
+
+func (bp BoolParser) StringE(value interface{}) (string, error) {
+    return strconv.FormatBool(value.(bool)), nil
+}
+
+func (bp BoolParser) String(value interface{}) string {
+    s, err := bp.StringE(value)
+    log.PanicIf(err)
+
+    return s
+}
+
+func (bp BoolParser) Int8E(value interface{}) (int8, error) {
+    if value.(bool) == true {
+        return 1, nil
+    }
+
+    return 0, nil
+}
+
+func (bp BoolParser) Int8(value interface{}) int8 {
+    p, err := bp.Int8E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (bp BoolParser) Int16E(value interface{}) (int16, error) {
+    if value.(bool) == true {
+        return 1, nil
+    }
+
+    return 0, nil
+}
+
+func (bp BoolParser) Int16(value interface{}) int16 {
+    p, err := bp.Int16E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (bp BoolParser) Int32E(value interface{}) (int32, error) {
+    if value.(bool) == true {
+        return 1, nil
+    }
+
+    return 0, nil
+}
+
+func (bp BoolParser) Int32(value interface{}) int32 {
+    p, err := bp.Int32E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (bp BoolParser) Int64E(value interface{}) (int64, error) {
+    if value.(bool) == true {
+        return 1, nil
+    }
+
+    return 0, nil
+}
+
+func (bp BoolParser) Int64(value interface{}) int64 {
+    p, err := bp.Int64E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (bp BoolParser) Uint8E(value interface{}) (uint8, error) {
+    if value.(bool) == true {
+        return 1, nil
+    }
+
+    return 0, nil
+}
+
+func (bp BoolParser) Uint8(value interface{}) uint8 {
+    p, err := bp.Uint8E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (bp BoolParser) Uint16E(value interface{}) (uint16, error) {
+    if value.(bool) == true {
+        return 1, nil
+    }
+
+    return 0, nil
+}
+
+func (bp BoolParser) Uint16(value interface{}) uint16 {
+    p, err := bp.Uint16E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (bp BoolParser) Uint32E(value interface{}) (uint32, error) {
+    if value.(bool) == true {
+        return 1, nil
+    }
+
+    return 0, nil
+}
+
+func (bp BoolParser) Uint32(value interface{}) uint32 {
+    p, err := bp.Uint32E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (bp BoolParser) Uint64E(value interface{}) (uint64, error) {
+    if value.(bool) == true {
+        return 1, nil
+    }
+
+    return 0, nil
+}
+
+func (bp BoolParser) Uint64(value interface{}) uint64 {
+    p, err := bp.Uint64E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (bp BoolParser) Hex8E(value interface{}) (uint8, error) {
+    if value.(bool) == true {
+        return 1, nil
+    }
+
+    return 0, nil
+}
+
+func (bp BoolParser) Hex8(value interface{}) uint8 {
+    p, err := bp.Hex8E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (bp BoolParser) Hex16E(value interface{}) (uint16, error) {
+    if value.(bool) == true {
+        return 1, nil
+    }
+
+    return 0, nil
+}
+
+func (bp BoolParser) Hex16(value interface{}) uint16 {
+    p, err := bp.Hex16E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (bp BoolParser) Hex32E(value interface{}) (uint32, error) {
+    if value.(bool) == true {
+        return 1, nil
+    }
+
+    return 0, nil
+}
+
+func (bp BoolParser) Hex32(value interface{}) uint32 {
+    p, err := bp.Hex32E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (bp BoolParser) Hex64E(value interface{}) (uint64, error) {
+    if value.(bool) == true {
+        return 1, nil
+    }
+
+    return 0, nil
+}
+
+func (bp BoolParser) Hex64(value interface{}) uint64 {
+    p, err := bp.Hex64E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (bp BoolParser) Float32E(value interface{}) (float32, error) {
+    if value.(bool) == true {
+        return 1, nil
+    }
+
+    return 0, nil
+}
+
+func (bp BoolParser) Float32(value interface{}) float32 {
+    p, err := bp.Float32E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (bp BoolParser) Float64E(value interface{}) (float64, error) {
+    if value.(bool) == true {
+        return 1, nil
+    }
+
+    return 0, nil
+}
+
+func (bp BoolParser) Float64(value interface{}) float64 {
+    p, err := bp.Float64E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (bp BoolParser) BoolE(value interface{}) (bool, error) {
+    return value.(bool), nil
+}
+
+func (bp BoolParser) Bool(value interface{}) bool {
+    p, err := bp.BoolE(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func init() {
+    p := NewBoolParser()
+    addBuiltinParser(reflect.TypeOf(false), p)
+}
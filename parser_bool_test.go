@@ -0,0 +1,35 @@
+package parse
+
+import (
+    "testing"
+    "errors"
+)
+
+func TestBoolParser(t *testing.T) {
+    phrases := [][]interface{} {
+        { true, "string", "true" },
+        { false, "string", "false" },
+        { true, "bool", true },
+        { false, "bool", false },
+        { true, "uint8", uint8(1) },
+        { false, "int64", int64(0) },
+        { true, "hex32", uint32(1) },
+        { true, "float64", float64(1) },
+    }
+
+    for _, phrase := range phrases {
+        value, err := ParseE(phrase[0], phrase[1].(string))
+        if err != nil {
+            t.Fatalf("Parse of [%v] to [%s] failed: [%s]", phrase[0], phrase[1], err)
+        } else if value != phrase[2] {
+            t.Fatalf("Parse of [%v] to [%s] not correct: [%v] (%T)", phrase[0], phrase[1], value, value)
+        }
+    }
+}
+
+func TestBoolParser_Rfc3339(t *testing.T) {
+    _, err := ParseE(true, "rfc3339")
+    if errors.Is(err, ErrUnsupportedKind) == false {
+        t.Fatalf("Expected unsupported-kind error: [%v]", err)
+    }
+}
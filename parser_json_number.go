@@ -0,0 +1,344 @@
+package parse
+
+import (
+    "fmt"
+    "math/big"
+    "reflect"
+    "strconv"
+    "strings"
+
+    "encoding/json"
+
+    "github.com/dsoprea/go-logging"
+)
+
+const (
+    // jsonNumberMaxExponent bounds the exponents that are evaluated exactly.
+    // Larger exponents can not produce a value that fits in any kind and
+    // would otherwise be expensive to evaluate.
+    jsonNumberMaxExponent = 400
+)
+
+// JsonNumberParser parses json.Number values, which JsonRequestParser
+// produces so that large integers do not lose precision as a float64.
+// Integer kinds are parsed exactly from the literal (including forms like
+// "1e3" and "3.0"). A fraction or a value that does not fit in the kind is
+// rejected with ErrRange. As with NumericParser, the hex kinds are treated
+// like the unsigned kinds and only 0 and 1 may be parsed as a bool.
+type JsonNumberParser struct {
+}
+
+func NewJsonNumberParser() *JsonNumberParser {
+    return new(JsonNumberParser)
+}
+
+// integer parses the number as an integer of the given size. The result is
+// an int64 if `signed` is true or a uint64 otherwise.
+func (jnp JsonNumberParser) integer(value interface{}, signed bool, bits int, kindName string) (p interface{}, err error) {
+    s := string(value.(json.Number))
+
+    // Fast path for plain integers.
+    if signed == true {
+        if i, err := strconv.ParseInt(s, 10, bits); err == nil {
+            return i, nil
+        }
+    } else if u, err := strconv.ParseUint(s, 10, bits); err == nil {
+        return u, nil
+    }
+
+    rangeError := fmt.Errorf("%w: value [%s] can not be represented as %s", ErrRange, s, kindName)
+
+    if _, exponent, found := strings.Cut(strings.ToLower(s), "e"); found == true {
+        e, err := strconv.Atoi(exponent)
+        if err != nil || e > jsonNumberMaxExponent || e < -jsonNumberMaxExponent {
+            return nil, rangeError
+        }
+    }
+
+    r, ok := new(big.Rat).SetString(s)
+    if ok == false {
+        return nil, fmt.Errorf("%w: value [%s] is not a number", ErrSyntax, s)
+    } else if r.IsInt() == false {
+        return nil, rangeError
+    }
+
+    i := r.Num()
+
+    if signed == true {
+        min := new(big.Int).Lsh(big.NewInt(-1), uint(bits - 1))
+        max := new(big.Int).Sub(new(big.Int).Neg(min), big.NewInt(1))
+
+        if i.Cmp(min) < 0 || i.Cmp(max) > 0 {
+            return nil, rangeError
+        }
+
+        return i.Int64(), nil
+    }
+
+    max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(bits)), big.NewInt(1))
+    if i.Sign() < 0 || i.Cmp(max) > 0 {
+        return nil, rangeError
+    }
+
+    return i.Uint64(), nil
+}
+
+func (jnp JsonNumberParser) StringE(value interface{}) (string, error) {
+    return string(value.(json.Number)), nil
+}
+
+func (jnp JsonNumberParser) String(value interface{}) string {
+    s, err := jnp.StringE(value)
+    log.PanicIf(err)
+
+    return s
+}
+
+func (jnp JsonNumberParser) Int8E(value interface{}) (int8, error) {
+    p, err := jnp.integer(value, true, 8, "int8")
+    if err != nil {
+        return 0, err
+    }
+
+    return int8(p.(int64)), nil
+}
+
+func (jnp JsonNumberParser) Int8(value interface{}) int8 {
+    p, err := jnp.Int8E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (jnp JsonNumberParser) Int16E(value interface{}) (int16, error) {
+    p, err := jnp.integer(value, true, 16, "int16")
+    if err != nil {
+        return 0, err
+    }
+
+    return int16(p.(int64)), nil
+}
+
+func (jnp JsonNumberParser) Int16(value interface{}) int16 {
+    p, err := jnp.Int16E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (jnp JsonNumberParser) Int32E(value interface{}) (int32, error) {
+    p, err := jnp.integer(value, true, 32, "int32")
+    if err != nil {
+        return 0, err
+    }
+
+    return int32(p.(int64)), nil
+}
+
+func (jnp JsonNumberParser) Int32(value interface{}) int32 {
+    p, err := jnp.Int32E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (jnp JsonNumberParser) Int64E(value interface{}) (int64, error) {
+    p, err := jnp.integer(value, true, 64, "int64")
+    if err != nil {
+        return 0, err
+    }
+
+    return int64(p.(int64)), nil
+}
+
+func (jnp JsonNumberParser) Int64(value interface{}) int64 {
+    p, err := jnp.Int64E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (jnp JsonNumberParser) Uint8E(value interface{}) (uint8, error) {
+    p, err := jnp.integer(value, false, 8, "uint8")
+    if err != nil {
+        return 0, err
+    }
+
+    return uint8(p.(uint64)), nil
+}
+
+func (jnp JsonNumberParser) Uint8(value interface{}) uint8 {
+    p, err := jnp.Uint8E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (jnp JsonNumberParser) Uint16E(value interface{}) (uint16, error) {
+    p, err := jnp.integer(value, false, 16, "uint16")
+    if err != nil {
+        return 0, err
+    }
+
+    return uint16(p.(uint64)), nil
+}
+
+func (jnp JsonNumberParser) Uint16(value interface{}) uint16 {
+    p, err := jnp.Uint16E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (jnp JsonNumberParser) Uint32E(value interface{}) (uint32, error) {
+    p, err := jnp.integer(value, false, 32, "uint32")
+    if err != nil {
+        return 0, err
+    }
+
+    return uint32(p.(uint64)), nil
+}
+
+func (jnp JsonNumberParser) Uint32(value interface{}) uint32 {
+    p, err := jnp.Uint32E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (jnp JsonNumberParser) Uint64E(value interface{}) (uint64, error) {
+    p, err := jnp.integer(value, false, 64, "uint64")
+    if err != nil {
+        return 0, err
+    }
+
+    return uint64(p.(uint64)), nil
+}
+
+func (jnp JsonNumberParser) Uint64(value interface{}) uint64 {
+    p, err := jnp.Uint64E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (jnp JsonNumberParser) Hex8E(value interface{}) (uint8, error) {
+    p, err := jnp.integer(value, false, 8, "hex8")
+    if err != nil {
+        return 0, err
+    }
+
+    return uint8(p.(uint64)), nil
+}
+
+func (jnp JsonNumberParser) Hex8(value interface{}) uint8 {
+    p, err := jnp.Hex8E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (jnp JsonNumberParser) Hex16E(value interface{}) (uint16, error) {
+    p, err := jnp.integer(value, false, 16, "hex16")
+    if err != nil {
+        return 0, err
+    }
+
+    return uint16(p.(uint64)), nil
+}
+
+func (jnp JsonNumberParser) Hex16(value interface{}) uint16 {
+    p, err := jnp.Hex16E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (jnp JsonNumberParser) Hex32E(value interface{}) (uint32, error) {
+    p, err := jnp.integer(value, false, 32, "hex32")
+    if err != nil {
+        return 0, err
+    }
+
+    return uint32(p.(uint64)), nil
+}
+
+func (jnp JsonNumberParser) Hex32(value interface{}) uint32 {
+    p, err := jnp.Hex32E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (jnp JsonNumberParser) Hex64E(value interface{}) (uint64, error) {
+    p, err := jnp.integer(value, false, 64, "hex64")
+    if err != nil {
+        return 0, err
+    }
+
+    return uint64(p.(uint64)), nil
+}
+
+func (jnp JsonNumberParser) Hex64(value interface{}) uint64 {
+    p, err := jnp.Hex64E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (jnp JsonNumberParser) Float32E(value interface{}) (float32, error) {
+    s := string(value.(json.Number))
+
+    p, err := strconv.ParseFloat(s, 32)
+    if err != nil {
+        return 0, err
+    }
+
+    return float32(p), nil
+}
+
+func (jnp JsonNumberParser) Float32(value interface{}) float32 {
+    p, err := jnp.Float32E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (jnp JsonNumberParser) Float64E(value interface{}) (float64, error) {
+    s := string(value.(json.Number))
+
+    p, err := strconv.ParseFloat(s, 64)
+    if err != nil {
+        return 0, err
+    }
+
+    return float64(p), nil
+}
+
+func (jnp JsonNumberParser) Float64(value interface{}) float64 {
+    p, err := jnp.Float64E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (jnp JsonNumberParser) BoolE(value interface{}) (bool, error) {
+    p, err := jnp.integer(value, false, 1, "bool")
+    if err != nil {
+        return false, err
+    }
+
+    return p.(uint64) == 1, nil
+}
+
+func (jnp JsonNumberParser) Bool(value interface{}) bool {
+    p, err := jnp.BoolE(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func init() {
+    p := NewJsonNumberParser()
+    addBuiltinParser(reflect.TypeOf(json.Number("")), p)
+}
@@ -0,0 +1,80 @@
+package parse
+
+import (
+    "testing"
+    "errors"
+    "strings"
+
+    "net/http"
+    "encoding/json"
+)
+
+func TestJsonNumberParser(t *testing.T) {
+    phrases := [][]interface{} {
+        { "9007199254740993", "uint64", uint64(9007199254740993) },
+        { "18446744073709551615", "uint64", uint64(18446744073709551615) },
+        { "-9223372036854775808", "int64", int64(-9223372036854775808) },
+        { "1e3", "uint16", uint16(1000) },
+        { "3.0", "int8", int8(3) },
+        { "-1.5e2", "int32", int32(-150) },
+        { "255", "hex8", uint8(255) },
+        { "1", "bool", true },
+        { "0.0", "bool", false },
+        { "0.25", "float32", float32(0.25) },
+        { "1.5e300", "float64", float64(1.5e300) },
+        { "12345678901234567890", "string", "12345678901234567890" },
+    }
+
+    for _, phrase := range phrases {
+        n := json.Number(phrase[0].(string))
+
+        value, err := ParseE(n, phrase[1].(string))
+        if err != nil {
+            t.Fatalf("Parse of [%s] to [%s] failed: [%s]", n, phrase[1], err)
+        } else if value != phrase[2] {
+            t.Fatalf("Parse of [%s] to [%s] not correct: [%v] (%T)", n, phrase[1], value, value)
+        }
+    }
+}
+
+func TestJsonNumberParser_Range(t *testing.T) {
+    phrases := [][]string {
+        { "18446744073709551616", "uint64" },
+        { "9223372036854775808", "int64" },
+        { "-1", "uint64" },
+        { "3.5", "int64" },
+        { "256", "uint8" },
+        { "1e20", "uint64" },
+        { "1e1000000000", "int64" },
+        { "1e-1000000000", "int64" },
+        { "2", "bool" },
+        { "1e39", "float32" },
+    }
+
+    for _, phrase := range phrases {
+        _, err := ParseE(json.Number(phrase[0]), phrase[1])
+        if errors.Is(err, ErrRange) == false {
+            t.Fatalf("Parse of [%s] to [%s] should fail with a range error: [%v]", phrase[0], phrase[1], err)
+        }
+    }
+}
+
+func TestJsonNumberParser_Syntax(t *testing.T) {
+    _, err := ParseE(json.Number("abc"), "int64")
+    if errors.Is(err, ErrSyntax) == false {
+        t.Fatalf("Expected syntax error: [%v]", err)
+    }
+}
+
+func TestJsonRequestParser_Precision(t *testing.T) {
+    req, err := http.NewRequest("POST", "http://example.com", strings.NewReader(`{"account_id": 9007199254740993}`))
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    jrp := NewJsonRequestParser(req)
+
+    if v := jrp.Get("account_id", "uint64", true).(uint64); v != 9007199254740993 {
+        t.Fatalf("Large integer lost precision: [%d]", v)
+    }
+}
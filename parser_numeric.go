@@ -0,0 +1,494 @@
+package parse
+
+import (
+    "fmt"
+    "math"
+    "reflect"
+    "strconv"
+
+    "github.com/dsoprea/go-logging"
+)
+
+// number is a numeric value normalized to the widest type of its class.
+type number struct {
+    value interface{}
+
+    isInt bool
+    isUint bool
+    isFloat bool
+
+    i int64
+    u uint64
+    f float64
+
+    // floatBits is the size of the original float (32 or 64).
+    floatBits int
+}
+
+// newNumber normalizes any Go integer or float value.
+func newNumber(value interface{}) (n number, err error) {
+    n.value = value
+
+    v := reflect.ValueOf(value)
+
+    switch v.Kind() {
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+        n.isInt = true
+        n.i = v.Int()
+    case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+        n.isUint = true
+        n.u = v.Uint()
+    case reflect.Float32, reflect.Float64:
+        n.isFloat = true
+        n.f = v.Float()
+        n.floatBits = v.Type().Bits()
+    default:
+        return n, fmt.Errorf("%w: value [%v] of type [%T] is not numeric", ErrUnsupportedKind, value, value)
+    }
+
+    return n, nil
+}
+
+func (n number) rangeError(kindName string) error {
+    return fmt.Errorf("%w: value [%v] can not be represented as %s", ErrRange, n.value, kindName)
+}
+
+// Int converts to a signed integer of the given size. Floats must be whole.
+func (n number) Int(bits int, kindName string) (int64, error) {
+    min := int64(-1) << (bits - 1)
+    max := int64(uint64(1) << (bits - 1) - 1)
+
+    if n.isInt == true {
+        if n.i < min || n.i > max {
+            return 0, n.rangeError(kindName)
+        }
+
+        return n.i, nil
+    } else if n.isUint == true {
+        if n.u > uint64(max) {
+            return 0, n.rangeError(kindName)
+        }
+
+        return int64(n.u), nil
+    }
+
+    // The upper bound is exclusive because max can not be represented
+    // exactly as a float64 for 64-bit integers.
+    if n.f != math.Trunc(n.f) || n.f < float64(min) || n.f >= -float64(min) {
+        return 0, n.rangeError(kindName)
+    }
+
+    return int64(n.f), nil
+}
+
+// Uint converts to an unsigned integer of the given size. Floats must be
+// whole.
+func (n number) Uint(bits int, kindName string) (uint64, error) {
+    max := uint64(math.MaxUint64) >> (64 - bits)
+
+    if n.isInt == true {
+        if n.i < 0 || uint64(n.i) > max {
+            return 0, n.rangeError(kindName)
+        }
+
+        return uint64(n.i), nil
+    } else if n.isUint == true {
+        if n.u > max {
+            return 0, n.rangeError(kindName)
+        }
+
+        return n.u, nil
+    }
+
+    // 2^bits is exactly representable as a float64.
+    if n.f != math.Trunc(n.f) || n.f < 0 || n.f >= math.Ldexp(1, bits) {
+        return 0, n.rangeError(kindName)
+    }
+
+    return uint64(n.f), nil
+}
+
+// Float converts to a float of the given size. Integers are converted to the
+// nearest float.
+func (n number) Float(bits int, kindName string) (float64, error) {
+    if n.isInt == true {
+        return float64(n.i), nil
+    } else if n.isUint == true {
+        return float64(n.u), nil
+    }
+
+    if bits == 32 && math.IsInf(n.f, 0) == false && math.Abs(n.f) > math.MaxFloat32 {
+        return 0, n.rangeError(kindName)
+    }
+
+    return n.f, nil
+}
+
+// String formats the number in base-10.
+func (n number) String() string {
+    if n.isInt == true {
+        return strconv.FormatInt(n.i, 10)
+    } else if n.isUint == true {
+        return strconv.FormatUint(n.u, 10)
+    }
+
+    return strconv.FormatFloat(n.f, 'g', -1, n.floatBits)
+}
+
+// NumericParser parses Go integer and float values (e.g. the float64 values
+// produced by encoding/json). Conversions are exact: a fraction is never
+// truncated (3.5 can not become an int64) and a value that does not fit in
+// the requested kind (300 as a uint8) is rejected with ErrRange. The hex
+// kinds are treated like the unsigned kinds. Only 0 and 1 may be parsed as a
+// bool.
+type NumericParser struct {
+}
+
+func NewNumericParser() *NumericParser {
+    return new(NumericParser)
+}
+
+func (np NumericParser) StringE(value interface{}) (string, error) {
+    n, err := newNumber(value)
+    if err != nil {
+        return "", err
+    }
+
+    return n.String(), nil
+}
+
+func (np NumericParser) String(value interface{}) string {
+    s, err := np.StringE(value)
+    log.PanicIf(err)
+
+    return s
+}
+
+func (np NumericParser) Int8E(value interface{}) (int8, error) {
+    n, err := newNumber(value)
+    if err != nil {
+        return 0, err
+    }
+
+    p, err := n.Int(8, "int8")
+    if err != nil {
+        return 0, err
+    }
+
+    return int8(p), nil
+}
+
+func (np NumericParser) Int8(value interface{}) int8 {
+    p, err := np.Int8E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (np NumericParser) Int16E(value interface{}) (int16, error) {
+    n, err := newNumber(value)
+    if err != nil {
+        return 0, err
+    }
+
+    p, err := n.Int(16, "int16")
+    if err != nil {
+        return 0, err
+    }
+
+    return int16(p), nil
+}
+
+func (np NumericParser) Int16(value interface{}) int16 {
+    p, err := np.Int16E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (np NumericParser) Int32E(value interface{}) (int32, error) {
+    n, err := newNumber(value)
+    if err != nil {
+        return 0, err
+    }
+
+    p, err := n.Int(32, "int32")
+    if err != nil {
+        return 0, err
+    }
+
+    return int32(p), nil
+}
+
+func (np NumericParser) Int32(value interface{}) int32 {
+    p, err := np.Int32E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (np NumericParser) Int64E(value interface{}) (int64, error) {
+    n, err := newNumber(value)
+    if err != nil {
+        return 0, err
+    }
+
+    p, err := n.Int(64, "int64")
+    if err != nil {
+        return 0, err
+    }
+
+    return p, nil
+}
+
+func (np NumericParser) Int64(value interface{}) int64 {
+    p, err := np.Int64E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (np NumericParser) Uint8E(value interface{}) (uint8, error) {
+    n, err := newNumber(value)
+    if err != nil {
+        return 0, err
+    }
+
+    p, err := n.Uint(8, "uint8")
+    if err != nil {
+        return 0, err
+    }
+
+    return uint8(p), nil
+}
+
+func (np NumericParser) Uint8(value interface{}) uint8 {
+    p, err := np.Uint8E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (np NumericParser) Uint16E(value interface{}) (uint16, error) {
+    n, err := newNumber(value)
+    if err != nil {
+        return 0, err
+    }
+
+    p, err := n.Uint(16, "uint16")
+    if err != nil {
+        return 0, err
+    }
+
+    return uint16(p), nil
+}
+
+func (np NumericParser) Uint16(value interface{}) uint16 {
+    p, err := np.Uint16E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (np NumericParser) Uint32E(value interface{}) (uint32, error) {
+    n, err := newNumber(value)
+    if err != nil {
+        return 0, err
+    }
+
+    p, err := n.Uint(32, "uint32")
+    if err != nil {
+        return 0, err
+    }
+
+    return uint32(p), nil
+}
+
+func (np NumericParser) Uint32(value interface{}) uint32 {
+    p, err := np.Uint32E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (np NumericParser) Uint64E(value interface{}) (uint64, error) {
+    n, err := newNumber(value)
+    if err != nil {
+        return 0, err
+    }
+
+    p, err := n.Uint(64, "uint64")
+    if err != nil {
+        return 0, err
+    }
+
+    return p, nil
+}
+
+func (np NumericParser) Uint64(value interface{}) uint64 {
+    p, err := np.Uint64E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (np NumericParser) Hex8E(value interface{}) (uint8, error) {
+    n, err := newNumber(value)
+    if err != nil {
+        return 0, err
+    }
+
+    p, err := n.Uint(8, "hex8")
+    if err != nil {
+        return 0, err
+    }
+
+    return uint8(p), nil
+}
+
+func (np NumericParser) Hex8(value interface{}) uint8 {
+    p, err := np.Hex8E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (np NumericParser) Hex16E(value interface{}) (uint16, error) {
+    n, err := newNumber(value)
+    if err != nil {
+        return 0, err
+    }
+
+    p, err := n.Uint(16, "hex16")
+    if err != nil {
+        return 0, err
+    }
+
+    return uint16(p), nil
+}
+
+func (np NumericParser) Hex16(value interface{}) uint16 {
+    p, err := np.Hex16E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (np NumericParser) Hex32E(value interface{}) (uint32, error) {
+    n, err := newNumber(value)
+    if err != nil {
+        return 0, err
+    }
+
+    p, err := n.Uint(32, "hex32")
+    if err != nil {
+        return 0, err
+    }
+
+    return uint32(p), nil
+}
+
+func (np NumericParser) Hex32(value interface{}) uint32 {
+    p, err := np.Hex32E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (np NumericParser) Hex64E(value interface{}) (uint64, error) {
+    n, err := newNumber(value)
+    if err != nil {
+        return 0, err
+    }
+
+    p, err := n.Uint(64, "hex64")
+    if err != nil {
+        return 0, err
+    }
+
+    return p, nil
+}
+
+func (np NumericParser) Hex64(value interface{}) uint64 {
+    p, err := np.Hex64E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (np NumericParser) Float32E(value interface{}) (float32, error) {
+    n, err := newNumber(value)
+    if err != nil {
+        return 0, err
+    }
+
+    p, err := n.Float(32, "float32")
+    if err != nil {
+        return 0, err
+    }
+
+    return float32(p), nil
+}
+
+func (np NumericParser) Float32(value interface{}) float32 {
+    p, err := np.Float32E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (np NumericParser) Float64E(value interface{}) (float64, error) {
+    n, err := newNumber(value)
+    if err != nil {
+        return 0, err
+    }
+
+    p, err := n.Float(64, "float64")
+    if err != nil {
+        return 0, err
+    }
+
+    return p, nil
+}
+
+func (np NumericParser) Float64(value interface{}) float64 {
+    p, err := np.Float64E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (np NumericParser) BoolE(value interface{}) (bool, error) {
+    n, err := newNumber(value)
+    if err != nil {
+        return false, err
+    }
+
+    p, err := n.Uint(1, "bool")
+    if err != nil {
+        return false, err
+    }
+
+    return p == 1, nil
+}
+
+func (np NumericParser) Bool(value interface{}) bool {
+    p, err := np.BoolE(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func init() {
+    p := NewNumericParser()
+
+    numericValues := []interface{} {
+        int(0), int8(0), int16(0), int32(0), int64(0),
+        uint(0), uint8(0), uint16(0), uint32(0), uint64(0),
+        float32(0), float64(0),
+    }
+
+    for _, v := range numericValues {
+        addBuiltinParser(reflect.TypeOf(v), p)
+    }
+}
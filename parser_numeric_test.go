@@ -0,0 +1,103 @@
+package parse
+
+import (
+    "testing"
+    "errors"
+    "math"
+    "strings"
+
+    "net/http"
+)
+
+func TestNumericParser_Exact(t *testing.T) {
+    phrases := [][]interface{} {
+        { float64(123), "uint64", uint64(123) },
+        { float64(-123), "int8", int8(-123) },
+        { float32(1.5), "float64", float64(1.5) },
+        { float64(1.5), "float32", float32(1.5) },
+        { int(255), "uint8", uint8(255) },
+        { uint64(math.MaxInt64), "int64", int64(math.MaxInt64) },
+        { int64(math.MinInt64), "int64", int64(math.MinInt64) },
+        { uint8(0xC4), "hex8", uint8(0xC4) },
+        { int32(-12), "float64", float64(-12) },
+        { float64(1), "bool", true },
+        { uint8(0), "bool", false },
+        { float64(-1.25), "string", "-1.25" },
+        { float32(0.1), "string", "0.1" },
+        { int16(-300), "string", "-300" },
+        { uint64(math.MaxUint64), "string", "18446744073709551615" },
+    }
+
+    for _, phrase := range phrases {
+        value, err := ParseE(phrase[0], phrase[1].(string))
+        if err != nil {
+            t.Fatalf("Parse of [%v] (%T) to [%s] failed: [%s]", phrase[0], phrase[0], phrase[1], err)
+        } else if value != phrase[2] {
+            t.Fatalf("Parse of [%v] (%T) to [%s] not correct: [%v] (%T)", phrase[0], phrase[0], phrase[1], value, value)
+        }
+    }
+}
+
+func TestNumericParser_Range(t *testing.T) {
+    phrases := [][]interface{} {
+        { float64(3.5), "int64" },
+        { int(300), "uint8" },
+        { int(-1), "uint64" },
+        { float64(-1), "uint32" },
+        { int(128), "int8" },
+        { int(-129), "int8" },
+        { uint64(math.MaxUint64), "int64" },
+        { float64(math.Pow(2, 63)), "int64" },
+        { float64(math.Pow(2, 64)), "uint64" },
+        { math.NaN(), "int64" },
+        { math.Inf(1), "uint64" },
+        { float64(math.MaxFloat64), "float32" },
+        { int(256), "hex8" },
+        { int(2), "bool" },
+    }
+
+    for _, phrase := range phrases {
+        _, err := ParseE(phrase[0], phrase[1].(string))
+        if errors.Is(err, ErrRange) == false {
+            t.Fatalf("Parse of [%v] (%T) to [%s] should fail with a range error: [%v]", phrase[0], phrase[0], phrase[1], err)
+        }
+    }
+}
+
+func TestNumericParser_Rfc3339(t *testing.T) {
+    _, err := ParseE(float64(123), "rfc3339")
+    if errors.Is(err, ErrUnsupportedKind) == false {
+        t.Fatalf("Expected unsupported-kind error: [%v]", err)
+    }
+}
+
+func TestNumericParser_NamedType(t *testing.T) {
+    type count int32
+
+    value, err := ParseE(count(-5), "int64")
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if value != int64(-5) {
+        t.Fatalf("Parsed value not correct: [%v]", value)
+    }
+}
+
+func TestJsonRequestParser_Numeric(t *testing.T) {
+    req, err := http.NewRequest("POST", "http://example.com", strings.NewReader(`{"account_id": 123, "ratio": 0.5, "limit": 3.5}`))
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    jrp := NewJsonRequestParser(req)
+
+    if v := jrp.Get("account_id", "uint64", true).(uint64); v != 123 {
+        t.Fatalf("account_id not correct: [%d]", v)
+    } else if v := jrp.Get("ratio", "float32", true).(float32); v != 0.5 {
+        t.Fatalf("ratio not correct: [%f]", v)
+    }
+
+    _, err = jrp.GetE("limit", "int64", true)
+    if errors.Is(err, ErrRange) == false {
+        t.Fatalf("Expected range error for fractional integer: [%v]", err)
+    }
+}
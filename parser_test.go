@@ -270,3 +270,12 @@ func TestParseRfc3339(t *testing.T) {
         }
     }
 }
+
+func TestParseInt8E_Range(t *testing.T) {
+    p := NewStringParser().(ParserE)
+
+    _, err := p.Int8E("300")
+    if err == nil {
+        t.Fatalf("Expected error for out-of-range value.")
+    }
+}
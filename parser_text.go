@@ -0,0 +1,363 @@
+package parse
+
+import (
+    "fmt"
+    "io"
+    "reflect"
+    "strings"
+    "time"
+
+    "encoding"
+    "encoding/json"
+
+    "github.com/dsoprea/go-logging"
+)
+
+var (
+    textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+    stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+    readerType = reflect.TypeOf((*io.Reader)(nil)).Elem()
+
+    // textMarshalerParser is used for values without a parser of their own
+    // that implement encoding.TextMarshaler.
+    textMarshalerParser = NewTextParser(func(value interface{}) (string, error) {
+        text, err := value.(encoding.TextMarshaler).MarshalText()
+        if err != nil {
+            return "", err
+        }
+
+        return string(text), nil
+    })
+
+    // stringerParser is used for values without a parser of their own that
+    // implement fmt.Stringer.
+    stringerParser = NewTextParser(func(value interface{}) (string, error) {
+        return value.(fmt.Stringer).String(), nil
+    })
+
+    // readerParser is used for values without a parser of their own that
+    // implement io.Reader. The reader is consumed.
+    readerParser = NewTextParser(func(value interface{}) (string, error) {
+        data, err := io.ReadAll(value.(io.Reader))
+        if err != nil {
+            return "", err
+        }
+
+        return string(data), nil
+    })
+)
+
+// TextParser parses values that have a textual form. The value is converted
+// to a string and then parsed by StringParser.
+type TextParser struct {
+    toString func(value interface{}) (string, error)
+    sp StringParser
+}
+
+// NewTextParser returns a parser that converts values to strings using
+// `toString` and parses them like StringParser.
+func NewTextParser(toString func(value interface{}) (string, error)) Parser {
+    return &TextParser{
+        toString: toString,
+    }
+}
+
+func (tp TextParser) StringE(value interface{}) (string, error) {
+    s, err := tp.toString(value)
+    if err != nil {
+        return "", err
+    }
+
+    return tp.sp.StringE(s)
+}
+
+func (tp TextParser) String(value interface{}) string {
+    p, err := tp.StringE(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (tp TextParser) Int8E(value interface{}) (int8, error) {
+    s, err := tp.toString(value)
+    if err != nil {
+        return 0, err
+    }
+
+    return tp.sp.Int8E(s)
+}
+
+func (tp TextParser) Int8(value interface{}) int8 {
+    p, err := tp.Int8E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (tp TextParser) Int16E(value interface{}) (int16, error) {
+    s, err := tp.toString(value)
+    if err != nil {
+        return 0, err
+    }
+
+    return tp.sp.Int16E(s)
+}
+
+func (tp TextParser) Int16(value interface{}) int16 {
+    p, err := tp.Int16E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (tp TextParser) Int32E(value interface{}) (int32, error) {
+    s, err := tp.toString(value)
+    if err != nil {
+        return 0, err
+    }
+
+    return tp.sp.Int32E(s)
+}
+
+func (tp TextParser) Int32(value interface{}) int32 {
+    p, err := tp.Int32E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (tp TextParser) Int64E(value interface{}) (int64, error) {
+    s, err := tp.toString(value)
+    if err != nil {
+        return 0, err
+    }
+
+    return tp.sp.Int64E(s)
+}
+
+func (tp TextParser) Int64(value interface{}) int64 {
+    p, err := tp.Int64E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (tp TextParser) Uint8E(value interface{}) (uint8, error) {
+    s, err := tp.toString(value)
+    if err != nil {
+        return 0, err
+    }
+
+    return tp.sp.Uint8E(s)
+}
+
+func (tp TextParser) Uint8(value interface{}) uint8 {
+    p, err := tp.Uint8E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (tp TextParser) Uint16E(value interface{}) (uint16, error) {
+    s, err := tp.toString(value)
+    if err != nil {
+        return 0, err
+    }
+
+    return tp.sp.Uint16E(s)
+}
+
+func (tp TextParser) Uint16(value interface{}) uint16 {
+    p, err := tp.Uint16E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (tp TextParser) Uint32E(value interface{}) (uint32, error) {
+    s, err := tp.toString(value)
+    if err != nil {
+        return 0, err
+    }
+
+    return tp.sp.Uint32E(s)
+}
+
+func (tp TextParser) Uint32(value interface{}) uint32 {
+    p, err := tp.Uint32E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (tp TextParser) Uint64E(value interface{}) (uint64, error) {
+    s, err := tp.toString(value)
+    if err != nil {
+        return 0, err
+    }
+
+    return tp.sp.Uint64E(s)
+}
+
+func (tp TextParser) Uint64(value interface{}) uint64 {
+    p, err := tp.Uint64E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (tp TextParser) Hex8E(value interface{}) (uint8, error) {
+    s, err := tp.toString(value)
+    if err != nil {
+        return 0, err
+    }
+
+    return tp.sp.Hex8E(s)
+}
+
+func (tp TextParser) Hex8(value interface{}) uint8 {
+    p, err := tp.Hex8E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (tp TextParser) Hex16E(value interface{}) (uint16, error) {
+    s, err := tp.toString(value)
+    if err != nil {
+        return 0, err
+    }
+
+    return tp.sp.Hex16E(s)
+}
+
+func (tp TextParser) Hex16(value interface{}) uint16 {
+    p, err := tp.Hex16E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (tp TextParser) Hex32E(value interface{}) (uint32, error) {
+    s, err := tp.toString(value)
+    if err != nil {
+        return 0, err
+    }
+
+    return tp.sp.Hex32E(s)
+}
+
+func (tp TextParser) Hex32(value interface{}) uint32 {
+    p, err := tp.Hex32E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (tp TextParser) Hex64E(value interface{}) (uint64, error) {
+    s, err := tp.toString(value)
+    if err != nil {
+        return 0, err
+    }
+
+    return tp.sp.Hex64E(s)
+}
+
+func (tp TextParser) Hex64(value interface{}) uint64 {
+    p, err := tp.Hex64E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (tp TextParser) Float32E(value interface{}) (float32, error) {
+    s, err := tp.toString(value)
+    if err != nil {
+        return 0, err
+    }
+
+    return tp.sp.Float32E(s)
+}
+
+func (tp TextParser) Float32(value interface{}) float32 {
+    p, err := tp.Float32E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (tp TextParser) Float64E(value interface{}) (float64, error) {
+    s, err := tp.toString(value)
+    if err != nil {
+        return 0, err
+    }
+
+    return tp.sp.Float64E(s)
+}
+
+func (tp TextParser) Float64(value interface{}) float64 {
+    p, err := tp.Float64E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (tp TextParser) BoolE(value interface{}) (bool, error) {
+    s, err := tp.toString(value)
+    if err != nil {
+        return false, err
+    }
+
+    return tp.sp.BoolE(s)
+}
+
+func (tp TextParser) Bool(value interface{}) bool {
+    p, err := tp.BoolE(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (tp TextParser) Rfc3339E(value interface{}) (time.Time, error) {
+    s, err := tp.toString(value)
+    if err != nil {
+        return time.Time{}, err
+    }
+
+    return tp.sp.Rfc3339E(s)
+}
+
+func (tp TextParser) Rfc3339(value interface{}) time.Time {
+    p, err := tp.Rfc3339E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+// bytesString converts a byte-slice to a string.
+func bytesString(value interface{}) (string, error) {
+    return string(value.([]byte)), nil
+}
+
+// rawMessageString converts raw JSON to a string. A JSON string is
+// unquoted. Any other JSON value (e.g. a number) is used as written.
+func rawMessageString(value interface{}) (string, error) {
+    raw := value.(json.RawMessage)
+
+    s := strings.TrimSpace(string(raw))
+    if strings.HasPrefix(s, "\"") == false {
+        return s, nil
+    }
+
+    err := json.Unmarshal(raw, &s)
+    if err != nil {
+        return "", fmt.Errorf("%w: %s", ErrSyntax, err.Error())
+    }
+
+    return s, nil
+}
+
+func init() {
+    addBuiltinParser(bytesType, NewTextParser(bytesString))
+    addBuiltinParser(reflect.TypeOf(json.RawMessage {}), NewTextParser(rawMessageString))
+}
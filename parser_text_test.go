@@ -0,0 +1,109 @@
+package parse
+
+import (
+    "testing"
+    "errors"
+    "net"
+    "reflect"
+    "strings"
+    "time"
+
+    "encoding/json"
+)
+
+type testStringer struct {
+    value string
+}
+
+func (ts testStringer) String() string {
+    return ts.value
+}
+
+type testCount int
+
+func (tc testCount) String() string {
+    return "count"
+}
+
+func TestTextParser_Bytes(t *testing.T) {
+    if value := Parse([]byte("123"), "uint16"); value != uint16(123) {
+        t.Fatalf("Bytes not parsed correctly: [%v]", value)
+    }
+
+    type rawValue []byte
+
+    if value := Parse(rawValue("-1.5"), "float64"); value != float64(-1.5) {
+        t.Fatalf("Named bytes not parsed correctly: [%v]", value)
+    }
+}
+
+func TestTextParser_RawMessage(t *testing.T) {
+    phrases := [][]interface{} {
+        { `"123"`, "uint64", uint64(123) },
+        { `123`, "uint64", uint64(123) },
+        { ` true `, "bool", true },
+        { `"a\"b"`, "string", `a"b` },
+    }
+
+    for _, phrase := range phrases {
+        raw := json.RawMessage(phrase[0].(string))
+
+        value, err := ParseE(raw, phrase[1].(string))
+        if err != nil {
+            t.Fatalf("Parse of [%s] failed: [%s]", raw, err)
+        } else if value != phrase[2] {
+            t.Fatalf("Parse of [%s] not correct: [%v]", raw, value)
+        }
+    }
+
+    _, err := ParseE(json.RawMessage(`"abc`), "string")
+    if errors.Is(err, ErrSyntax) == false {
+        t.Fatalf("Expected syntax error: [%v]", err)
+    }
+}
+
+func TestTextParser_TextMarshaler(t *testing.T) {
+    // net.IP is a byte-slice but its text form must be used.
+    ip := net.ParseIP("10.0.0.1")
+
+    if value := Parse(ip, "string"); value != "10.0.0.1" {
+        t.Fatalf("TextMarshaler not used: [%v]", value)
+    } else if value := Parse(&ip, "string"); value != "10.0.0.1" {
+        t.Fatalf("TextMarshaler not used through pointer: [%v]", value)
+    }
+}
+
+func TestTextParser_Stringer(t *testing.T) {
+    if value := Parse(testStringer{ value: "12" }, "int32"); value != int32(12) {
+        t.Fatalf("Stringer not used: [%v]", value)
+    }
+
+    // The underlying kind is preferred to fmt.Stringer.
+    if value := Parse(testCount(5), "int64"); value != int64(5) {
+        t.Fatalf("Underlying kind not preferred: [%v]", value)
+    }
+
+    var nilStringer *testStringer
+
+    if _, err := ParseE(nilStringer, "int32"); err != nil {
+        t.Fatalf("Nil pointer should be treated as nil: [%s]", err)
+    }
+}
+
+func TestTextParser_ExactMatchPreferred(t *testing.T) {
+    // time.Time implements both encoding.TextMarshaler and fmt.Stringer.
+    now := time.Now()
+
+    p, found := DefaultRegistry.lookupParser(reflect.TypeOf(&now))
+    if found == false {
+        t.Fatalf("Time pointer not matched.")
+    } else if _, ok := p.(*TimeParser); ok == false {
+        t.Fatalf("TimeParser was not preferred: [%T]", p)
+    }
+}
+
+func TestTextParser_Reader(t *testing.T) {
+    if value := Parse(strings.NewReader("0.5"), "float32"); value != float32(0.5) {
+        t.Fatalf("Reader not used: [%v]", value)
+    }
+}
@@ -0,0 +1,57 @@
+package parse
+
+import (
+    "time"
+
+    "github.com/dsoprea/go-logging"
+)
+
+// TimeParser parses time.Time values. They may be formatted as an RFC 3339
+// string, converted to Unix seconds ("int64"), or passed through
+// ("rfc3339"). It only implements the capabilities for those kinds.
+type TimeParser struct {
+}
+
+func NewTimeParser() *TimeParser {
+    return new(TimeParser)
+}
+
+func (tp TimeParser) StringE(value interface{}) (string, error) {
+    t := value.(time.Time)
+    return t.Format(time.RFC3339Nano), nil
+}
+
+func (tp TimeParser) String(value interface{}) string {
+    s, err := tp.StringE(value)
+    log.PanicIf(err)
+
+    return s
+}
+
+func (tp TimeParser) Int64E(value interface{}) (int64, error) {
+    t := value.(time.Time)
+    return t.Unix(), nil
+}
+
+func (tp TimeParser) Int64(value interface{}) int64 {
+    p, err := tp.Int64E(value)
+    log.PanicIf(err)
+
+    return p
+}
+
+func (tp TimeParser) Rfc3339E(value interface{}) (time.Time, error) {
+    return value.(time.Time), nil
+}
+
+func (tp TimeParser) Rfc3339(value interface{}) time.Time {
+    t, err := tp.Rfc3339E(value)
+    log.PanicIf(err)
+
+    return t
+}
+
+func init() {
+    p := NewTimeParser()
+    addBuiltinParser(timeType, p)
+}
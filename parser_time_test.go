@@ -0,0 +1,34 @@
+package parse
+
+import (
+    "testing"
+    "errors"
+    "time"
+)
+
+func TestTimeParser(t *testing.T) {
+    now := time.Date(2016, 11, 8, 19, 32, 29, 500000000, time.FixedZone("", 12 * 3600 + 34 * 60))
+
+    if value := Parse(now, "string"); value != "2016-11-08T19:32:29.5+12:34" {
+        t.Fatalf("String not correct: [%v]", value)
+    } else if value := Parse(now, "int64"); value != now.Unix() {
+        t.Fatalf("Unix seconds not correct: [%v]", value)
+    } else if value := Parse(now, "rfc3339").(time.Time); value.Equal(now) == false {
+        t.Fatalf("Time not correct: [%v]", value)
+    }
+
+    // The string form can be parsed back.
+    s := Parse(now, "string")
+    if value := Parse(s, "rfc3339").(time.Time); value.Equal(now) == false {
+        t.Fatalf("Round-trip not correct: [%v]", value)
+    }
+}
+
+func TestTimeParser_Unsupported(t *testing.T) {
+    for _, kindName := range []string { "uint64", "int32", "hex64", "float64", "bool" } {
+        _, err := ParseE(time.Now(), kindName)
+        if errors.Is(err, ErrUnsupportedKind) == false {
+            t.Fatalf("Expected unsupported-kind error for [%s]: [%v]", kindName, err)
+        }
+    }
+}
@@ -0,0 +1,88 @@
+package parse
+
+import (
+    "errors"
+    "fmt"
+
+    "net/http"
+    "encoding/json"
+)
+
+const (
+    // ProblemContentType is the media-type of RFC 7807 problem details.
+    ProblemContentType = "application/problem+json"
+)
+
+// InvalidParam describes one invalid parameter in a Problem.
+type InvalidParam struct {
+    Name string `json:"name"`
+    Source string `json:"source,omitempty"`
+    Reason string `json:"reason"`
+}
+
+// Problem is an RFC 7807 problem-details document for parse failures.
+type Problem struct {
+    Type string `json:"type"`
+    Title string `json:"title"`
+    Status int `json:"status"`
+    Detail string `json:"detail,omitempty"`
+    InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// newInvalidParam describes a single *ParseError. The argument name is
+// preferred over the struct-field path.
+func newInvalidParam(pe *ParseError) InvalidParam {
+    name := pe.Name()
+    if name == "" {
+        name = pe.Field()
+    }
+
+    return InvalidParam{
+        Name: name,
+        Source: pe.Source(),
+        Reason: pe.Error(),
+    }
+}
+
+// NewProblem describes a *ParseError or ParseErrors as a Problem with status
+// 400. Any other error is only reported in the detail.
+func NewProblem(err error) *Problem {
+    p := &Problem{
+        Type: "about:blank",
+        Title: http.StatusText(http.StatusBadRequest),
+        Status: http.StatusBadRequest,
+    }
+
+    var pes ParseErrors
+    var pe *ParseError
+
+    if errors.As(err, &pes) == true {
+        p.InvalidParams = make([]InvalidParam, len(pes))
+        for i, pe := range pes {
+            p.InvalidParams[i] = newInvalidParam(pe)
+        }
+    } else if errors.As(err, &pe) == true {
+        p.InvalidParams = []InvalidParam { newInvalidParam(pe) }
+    } else {
+        p.Detail = err.Error()
+        return p
+    }
+
+    p.Detail = fmt.Sprintf("%d request parameter(s) not valid", len(p.InvalidParams))
+
+    return p
+}
+
+// WriteProblem writes the parse failure as an "application/problem+json"
+// response with status 400.
+func WriteProblem(w http.ResponseWriter, err error) {
+    p := NewProblem(err)
+
+    w.Header().Set("Content-Type", ProblemContentType)
+    w.Header().Set("X-Content-Type-Options", "nosniff")
+    w.WriteHeader(p.Status)
+
+    // The status has already been sent. There is nothing left to do if the
+    // body can not be written.
+    json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,80 @@
+package parse
+
+import (
+    "testing"
+    "errors"
+    "reflect"
+
+    "net/http"
+    "net/http/httptest"
+    "encoding/json"
+)
+
+func TestWriteProblem_ParseErrors(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com?limit=abc", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    err = BindAllE(req, &testBindRequest{})
+
+    w := httptest.NewRecorder()
+    WriteProblem(w, err)
+
+    if w.Code != http.StatusBadRequest {
+        t.Fatalf("Status not correct: (%d)", w.Code)
+    } else if w.Header().Get("Content-Type") != ProblemContentType {
+        t.Fatalf("Content-type not correct: [%s]", w.Header().Get("Content-Type"))
+    }
+
+    p := Problem{}
+
+    err = json.Unmarshal(w.Body.Bytes(), &p)
+    if err != nil {
+        t.Fatalf("Could not decode problem: [%s]", err)
+    }
+
+    expected := Problem{
+        Type: "about:blank",
+        Title: "Bad Request",
+        Status: http.StatusBadRequest,
+        Detail: "2 request parameter(s) not valid",
+        InvalidParams: []InvalidParam {
+            { Name: "account_id", Source: SourceQuery, Reason: "query argument empty or omitted: [account_id]" },
+            { Name: "limit", Source: SourceQuery, Reason: "strconv.ParseInt: parsing \"abc\": invalid syntax" },
+        },
+    }
+
+    if reflect.DeepEqual(p, expected) == false {
+        t.Fatalf("Problem not correct: %v", p)
+    }
+}
+
+func TestNewProblem_ParseError(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    _, err = FromRequestHeaderE(req, "X-Id", "uint64", true)
+
+    p := NewProblem(err)
+    if len(p.InvalidParams) != 1 {
+        t.Fatalf("Expected one invalid parameter: %v", p.InvalidParams)
+    }
+
+    ip := p.InvalidParams[0]
+    if ip.Name != "X-Id" || ip.Source != SourceHeader {
+        t.Fatalf("Invalid parameter not correct: %v", ip)
+    }
+}
+
+func TestNewProblem_OtherError(t *testing.T) {
+    p := NewProblem(errors.New("some failure"))
+
+    if p.Detail != "some failure" {
+        t.Fatalf("Detail not correct: [%s]", p.Detail)
+    } else if p.InvalidParams != nil {
+        t.Fatalf("Expected no invalid parameters: %v", p.InvalidParams)
+    }
+}
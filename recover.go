@@ -0,0 +1,61 @@
+package parse
+
+import (
+    "reflect"
+
+    "net/http"
+)
+
+var (
+    errorType = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// unwrapPanicError returns the error that go-logging wrapped when panicking.
+// Its wrapper (from go-errors) keeps the original in an `Err` field and does
+// not implement Unwrap() in every version.
+func unwrapPanicError(err error) error {
+    v := reflect.ValueOf(err)
+    if v.Kind() != reflect.Ptr || v.IsNil() == true || v.Elem().Kind() != reflect.Struct {
+        return err
+    }
+
+    f := v.Elem().FieldByName("Err")
+    if f.IsValid() == false || f.Type() != errorType || f.IsNil() == true {
+        return err
+    }
+
+    return f.Interface().(error)
+}
+
+// RecoverHandler wraps a handler so that panics from this package's
+// panicking functions (e.g. FromRequestQuery) that were caused by bad input
+// are written as a 400 problem-details response (see WriteProblem). Any other
+// panic, including a ParseError caused by a programming error such as an
+// unknown kind, is re-raised unchanged.
+//
+// The response can only be written if the handler has not already written
+// its header.
+func RecoverHandler(next http.Handler) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        defer func() {
+            errRaw := recover()
+            if errRaw == nil {
+                return
+            }
+
+            err, ok := errRaw.(error)
+            if ok == false {
+                panic(errRaw)
+            }
+
+            err = unwrapPanicError(err)
+            if IsClientError(err) == false {
+                panic(errRaw)
+            }
+
+            WriteProblem(w, err)
+        }()
+
+        next.ServeHTTP(w, r)
+    })
+}
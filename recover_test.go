@@ -0,0 +1,164 @@
+package parse
+
+import (
+    "testing"
+    "errors"
+    "reflect"
+    "runtime"
+    "strings"
+
+    "net/http"
+    "net/http/httptest"
+)
+
+func TestRecoverHandler_ParseError(t *testing.T) {
+    h := RecoverHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        FromRequestQuery(r, "aa", "uint64", true)
+        w.WriteHeader(http.StatusOK)
+    }))
+
+    req := httptest.NewRequest("GET", "http://example.com?aa=abc", nil)
+    w := httptest.NewRecorder()
+
+    h.ServeHTTP(w, req)
+
+    if w.Code != http.StatusBadRequest {
+        t.Fatalf("Status not correct: (%d)", w.Code)
+    } else if w.Header().Get("Content-Type") != ProblemContentType {
+        t.Fatalf("Content-type not correct: [%s]", w.Header().Get("Content-Type"))
+    }
+}
+
+func TestRecoverHandler_BindAll(t *testing.T) {
+    h := RecoverHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        BindAll(r, &testBindRequest{})
+        w.WriteHeader(http.StatusOK)
+    }))
+
+    req := httptest.NewRequest("GET", "http://example.com", nil)
+    w := httptest.NewRecorder()
+
+    h.ServeHTTP(w, req)
+
+    if w.Code != http.StatusBadRequest {
+        t.Fatalf("Status not correct: (%d)", w.Code)
+    }
+}
+
+func TestRecoverHandler_TypeMismatch(t *testing.T) {
+    h := RecoverHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        jrp := NewJsonRequestParser(r)
+        jrp.Get("limit", "int64", true)
+        jrp.Get("since", "rfc3339", true)
+        w.WriteHeader(http.StatusOK)
+    }))
+
+    bodies := []string {
+        `{"limit": [1], "since": "2020-01-01T00:00:00Z"}`,
+        `{"limit": {"value": 1}, "since": "2020-01-01T00:00:00Z"}`,
+        `{"limit": 1, "since": true}`,
+    }
+
+    for _, body := range bodies {
+        req := httptest.NewRequest("POST", "http://example.com", strings.NewReader(body))
+        w := httptest.NewRecorder()
+
+        h.ServeHTTP(w, req)
+
+        if w.Code != http.StatusBadRequest {
+            t.Fatalf("Status not correct for body [%s]: (%d)", body, w.Code)
+        }
+    }
+}
+
+func TestRecoverHandler_UnsupportedKind(t *testing.T) {
+    h := RecoverHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        FromRequestQuery(r, "aa", "invalid-kind", true)
+    }))
+
+    defer func() {
+        errRaw := recover()
+        if errRaw == nil {
+            t.Fatalf("Expected programming error to be re-raised.")
+        }
+
+        // The original panic is re-raised, so it is still wrapped by
+        // go-logging.
+        err := unwrapPanicError(errRaw.(error))
+        if errors.Is(err, ErrUnsupportedKind) == false {
+            t.Fatalf("Re-raised error not correct: [%v]", err)
+        }
+    }()
+
+    req := httptest.NewRequest("GET", "http://example.com?aa=123", nil)
+    h.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestRecoverHandler_OtherPanic(t *testing.T) {
+    h := RecoverHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        panic("some bug")
+    }))
+
+    defer func() {
+        errRaw := recover()
+        if errRaw != "some bug" {
+            t.Fatalf("Expected original panic to be re-raised: [%v]", errRaw)
+        }
+    }()
+
+    req := httptest.NewRequest("GET", "http://example.com", nil)
+    h.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+// testBuggyParser has a bug: it writes to a nil map.
+type testBuggyParser struct {
+    calls map[string]int
+}
+
+func (tbp testBuggyParser) Int64E(value interface{}) (int64, error) {
+    tbp.calls["Int64E"]++
+    return 0, nil
+}
+
+type testBuggyValue string
+
+func TestRecoverHandler_RuntimeError(t *testing.T) {
+    r := NewRegistry()
+    r.AddParser(reflect.TypeOf(testBuggyValue("")), testBuggyParser{})
+
+    r.AddKind("buggy", reflect.TypeOf(int64(0)), func(valueRaw interface{}) (interface{}, error) {
+        var calls map[string]int
+        calls["convert"]++
+
+        return int64(0), nil
+    })
+
+    cases := []struct {
+        valueRaw interface{}
+        kindName string
+    }{
+        { testBuggyValue("123"), "int64" },
+        { "123", "buggy" },
+    }
+
+    for _, c := range cases {
+        func() {
+            h := RecoverHandler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+                r.Parse(c.valueRaw, c.kindName)
+                w.WriteHeader(http.StatusOK)
+            }))
+
+            w := httptest.NewRecorder()
+
+            defer func() {
+                errRaw := recover()
+                if _, ok := errRaw.(runtime.Error); ok == false {
+                    t.Fatalf("Expected runtime error to be re-raised for kind [%s]: [%v] (%d)", c.kindName, errRaw, w.Code)
+                }
+            }()
+
+            req := httptest.NewRequest("GET", "http://example.com", nil)
+            h.ServeHTTP(w, req)
+        }()
+    }
+}
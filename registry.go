@@ -0,0 +1,529 @@
+package parse
+
+import (
+    "errors"
+    "fmt"
+    "reflect"
+    "sync"
+
+    "github.com/dsoprea/go-logging"
+)
+
+var (
+    // ErrRegistryFrozen is raised when registering with a frozen registry.
+    ErrRegistryFrozen = errors.New("registry is frozen")
+)
+
+var (
+    // builtinParsers are the parsers included with this package. Every new
+    // registry starts with them.
+    builtinParsers = map[reflect.Type]*parserEntry {}
+
+    // DefaultRegistry is used by the package-level functions (e.g. Parse()
+    // and AddParser()).
+    DefaultRegistry = NewRegistry()
+
+    bytesType = reflect.TypeOf([]byte {})
+
+    textMarshalerEntry = newParserEntry(textMarshalerParser)
+    stringerEntry = newParserEntry(stringerParser)
+    readerEntry = newParserEntry(readerParser)
+
+    // underlyingTypes are the unnamed types that values of named types fall
+    // back to, by kind.
+    underlyingTypes = map[reflect.Kind]reflect.Type {
+        reflect.String: stringType,
+        reflect.Bool: reflect.TypeOf(false),
+        reflect.Int: reflect.TypeOf(int(0)),
+        reflect.Int8: reflect.TypeOf(int8(0)),
+        reflect.Int16: reflect.TypeOf(int16(0)),
+        reflect.Int32: reflect.TypeOf(int32(0)),
+        reflect.Int64: reflect.TypeOf(int64(0)),
+        reflect.Uint: reflect.TypeOf(uint(0)),
+        reflect.Uint8: reflect.TypeOf(uint8(0)),
+        reflect.Uint16: reflect.TypeOf(uint16(0)),
+        reflect.Uint32: reflect.TypeOf(uint32(0)),
+        reflect.Uint64: reflect.TypeOf(uint64(0)),
+        reflect.Float32: reflect.TypeOf(float32(0)),
+        reflect.Float64: reflect.TypeOf(float64(0)),
+    }
+)
+
+// underlyingType returns the unnamed type that the given named type may be
+// converted to (e.g. string for `type UserId string`).
+func underlyingType(t reflect.Type) (ut reflect.Type, found bool) {
+    if t.Kind() == reflect.Slice {
+        ut = bytesType
+    } else if ut, found = underlyingTypes[t.Kind()]; found == false {
+        return nil, false
+    }
+
+    if ut == t || t.ConvertibleTo(ut) == false {
+        return nil, false
+    }
+
+    return ut, true
+}
+
+// Registry is a set of parsers and kinds. It is safe for concurrent use. Separate
+// registries may be created to isolate services or tests from each other.
+type Registry struct {
+    lock sync.RWMutex
+
+    // parsers are keyed by the exact type that they accept. Each implements
+    // Parser or one or more of the per-kind capabilities (e.g.
+    // Int64KindParser).
+    parsers map[reflect.Type]*parserEntry
+
+    // kinds are the kinds that values may be parsed to, by name.
+    kinds map[string]kindDef
+
+    // conversions are registered with AddConversion().
+    conversions map[conversionKey]ConvertFunc
+
+    frozen bool
+}
+
+// NewRegistry returns a registry with the built-in parsers (e.g.
+// StringParser) and kinds (e.g. "uint64") registered.
+func NewRegistry() *Registry {
+    r := &Registry{
+        parsers: make(map[reflect.Type]*parserEntry),
+        kinds: builtinKinds(),
+        conversions: make(map[conversionKey]ConvertFunc),
+    }
+
+    for fromType, pe := range builtinParsers {
+        r.parsers[fromType] = pe
+    }
+
+    return r
+}
+
+// addBuiltinParser registers a parser with every registry that is created
+// later and with the default registry.
+func addBuiltinParser(fromType reflect.Type, p interface{}) {
+    pe := newParserEntry(p)
+
+    builtinParsers[fromType] = pe
+    DefaultRegistry.addParserEntry(fromType, pe)
+}
+
+// AddParser registers a parser for values of exactly the given type. Types
+// are distinguished by identity (including package path), so two named types
+// with the same name in different packages are distinct, as are different
+// unnamed types (e.g. []byte and map[string]string). Named types do not match
+// their underlying type exactly but fall back to it (see match()).
+//
+// The parser may implement the whole Parser interface or only the per-kind
+// capabilities that it supports (e.g. Int64KindParser and BoolKindParser).
+// It panics if the parser does not support any kind, or with
+// ErrRegistryFrozen if the registry has been frozen.
+func (r *Registry) AddParser(fromType reflect.Type, p interface{}) {
+    r.addParserEntry(fromType, newParserEntry(p))
+}
+
+func (r *Registry) addParserEntry(fromType reflect.Type, pe *parserEntry) {
+    r.lock.Lock()
+    defer r.lock.Unlock()
+
+    if r.frozen == true {
+        log.Panic(ErrRegistryFrozen)
+    }
+
+    r.parsers[fromType] = pe
+}
+
+// Freeze prevents any more parsers from being registered. This may be used
+// to guarantee that the parsers do not change once requests are being
+// served.
+func (r *Registry) Freeze() {
+    r.lock.Lock()
+    defer r.lock.Unlock()
+
+    r.frozen = true
+}
+
+// IsFrozen indicates whether Freeze() has been called.
+func (r *Registry) IsFrozen() bool {
+    r.lock.RLock()
+    defer r.lock.RUnlock()
+
+    return r.frozen
+}
+
+// Clone returns an unfrozen copy of the registry.
+func (r *Registry) Clone() *Registry {
+    r.lock.RLock()
+    defer r.lock.RUnlock()
+
+    clone := &Registry{
+        parsers: make(map[reflect.Type]*parserEntry, len(r.parsers)),
+        kinds: make(map[string]kindDef, len(r.kinds)),
+        conversions: make(map[conversionKey]ConvertFunc, len(r.conversions)),
+    }
+
+    for fromType, pe := range r.parsers {
+        clone.parsers[fromType] = pe
+    }
+
+    for kindName, kd := range r.kinds {
+        clone.kinds[kindName] = kd
+    }
+
+    for ck, convert := range r.conversions {
+        clone.conversions[ck] = convert
+    }
+
+    return clone
+}
+
+// parserMatch describes how a parser was found for a type.
+type parserMatch struct {
+    pe *parserEntry
+
+    // depth is the number of pointers that must be dereferenced.
+    depth int
+
+    // convertTo is the underlying type that the value must be converted to,
+    // if any.
+    convertTo reflect.Type
+}
+
+// match finds the parser for the given type. A pointer is dereferenced as
+// many times as required. A type is matched in this order:
+//
+// 1. Exactly, at any pointer depth (so *time.Time matches time.Time).
+// 2. Then, at each pointer depth in turn:
+//    a. As an encoding.TextMarshaler, using its text.
+//    b. By its underlying type, if it is a named type with a basic
+//       underlying kind (e.g. `type UserId string` matches string and
+//       `type Raw []byte` matches []byte).
+//    c. As a fmt.Stringer, using its string.
+//    d. As an io.Reader, using everything that it produces.
+func (r *Registry) match(fromType reflect.Type) (pm parserMatch, found bool) {
+    r.lock.RLock()
+    defer r.lock.RUnlock()
+
+    for depth, t := 0, fromType; ; depth++ {
+        if pe, found := r.parsers[t]; found == true {
+            return parserMatch{ pe: pe, depth: depth }, true
+        }
+
+        if t.Kind() != reflect.Ptr {
+            break
+        }
+
+        t = t.Elem()
+    }
+
+    for depth, t := 0, fromType; ; depth++ {
+        if t.Implements(textMarshalerType) == true {
+            return parserMatch{ pe: textMarshalerEntry, depth: depth }, true
+        }
+
+        if ut, found := underlyingType(t); found == true {
+            if pe, found := r.parsers[ut]; found == true {
+                return parserMatch{ pe: pe, depth: depth, convertTo: ut }, true
+            }
+        }
+
+        if t.Implements(stringerType) == true {
+            return parserMatch{ pe: stringerEntry, depth: depth }, true
+        }
+
+        if t.Implements(readerType) == true {
+            return parserMatch{ pe: readerEntry, depth: depth }, true
+        }
+
+        if t.Kind() != reflect.Ptr {
+            return pm, false
+        }
+
+        t = t.Elem()
+    }
+}
+
+// apply prepares a value for the matched parser by dereferencing and
+// converting it. `isNil` is true if the value is or points to a nil pointer.
+func (pm parserMatch) apply(valueRaw interface{}) (value interface{}, isNil bool) {
+    if pm.depth == 0 && pm.convertTo == nil && reflect.TypeOf(valueRaw).Kind() != reflect.Ptr {
+        // The common case (an exact match) does not require reflection.
+        return valueRaw, false
+    }
+
+    v := reflect.ValueOf(valueRaw)
+
+    for i := 0; i < pm.depth; i++ {
+        if v.IsNil() == true {
+            return nil, true
+        }
+
+        v = v.Elem()
+    }
+
+    if v.Kind() == reflect.Ptr && v.IsNil() == true {
+        return nil, true
+    }
+
+    if pm.convertTo != nil {
+        v = v.Convert(pm.convertTo)
+    }
+
+    return v.Interface(), false
+}
+
+// lookupParser finds the parser for the given type. See match().
+func (r *Registry) lookupParser(fromType reflect.Type) (p interface{}, found bool) {
+    pm, found := r.match(fromType)
+    if found == false {
+        return nil, false
+    }
+
+    return pm.pe.p, true
+}
+
+// GetParser returns the parser for the given type. See AddParser() and
+// match() for how types are matched. Note that a parser matched through a
+// pointer or by underlying type expects values of the type that it was
+// registered for. It panics if the parser only implements some of the
+// per-kind capabilities.
+func (r *Registry) GetParser(fromType reflect.Type) Parser {
+    p, found := r.lookupParser(fromType)
+    if found == false {
+        log.Panic(fmt.Errorf("no parser registered for type [%s]", fromType))
+    }
+
+    fp, ok := p.(Parser)
+    if ok == false {
+        log.Panic(fmt.Errorf("parser [%T] for type [%s] does not implement every kind", p, fromType))
+    }
+
+    return fp
+}
+
+// Parse parses the given value to the given kind. It panics on failure.
+func (r *Registry) Parse(valueRaw interface{}, toKindName string) interface{} {
+    value, err := r.ParseE(valueRaw, toKindName)
+    log.PanicIf(err)
+
+    return value
+}
+
+// zeroValue produces the result of parsing a nil value: the zero-value of
+// the kind's type (e.g. uint64(0) for "uint64").
+func (r *Registry) zeroValue(toKindName string) (value interface{}, err error) {
+    t, found := r.kindType(toKindName)
+    if found == false {
+        return nil, newParseError(toKindName, nil, ErrUnsupportedKind, nil, fmt.Sprintf("kind [%s] does not have a zero-type defined", toKindName))
+    }
+
+    return reflect.Zero(t).Interface(), nil
+}
+
+// isNilValue indicates whether the value is nil or is a (possibly indirect)
+// nil pointer.
+func isNilValue(valueRaw interface{}) bool {
+    if valueRaw == nil {
+        return true
+    }
+
+    v := reflect.ValueOf(valueRaw)
+    for v.Kind() == reflect.Ptr {
+        if v.IsNil() == true {
+            return true
+        }
+
+        v = v.Elem()
+    }
+
+    return false
+}
+
+// ParseOr is like Parse but produces the given default for a nil value (or a
+// nil pointer). `present` is false if the default was used. It panics on
+// failure.
+func (r *Registry) ParseOr(valueRaw interface{}, toKindName string, defaultValue interface{}) (value interface{}, present bool) {
+    value, present, err := r.ParseOrE(valueRaw, toKindName, defaultValue)
+    log.PanicIf(err)
+
+    return value, present
+}
+
+// ParseOrE is the error-returning variant of ParseOr. A nil default produces
+// the zero-value of the kind's type. A default that does not have the kind's
+// type is parsed like any other value (e.g. 10 or "10" for "uint64").
+func (r *Registry) ParseOrE(valueRaw interface{}, toKindName string, defaultValue interface{}) (value interface{}, present bool, err error) {
+    if isNilValue(valueRaw) == false {
+        value, err = r.ParseE(valueRaw, toKindName)
+        return value, true, err
+    }
+
+    if t, found := r.kindType(toKindName); found == true && reflect.TypeOf(defaultValue) == t {
+        return defaultValue, false, nil
+    }
+
+    value, err = r.ParseE(defaultValue, toKindName)
+    return value, false, err
+}
+
+// ParseE parses the given value to the given kind. Failures are returned as
+// a *ParseError rather than panicking. A conversion registered for the exact
+// type of the value is used first (see AddConversion()). Otherwise, the
+// kind's own conversion is used or, for the built-in kinds, the parser
+// registered for the type.
+func (r *Registry) ParseE(valueRaw interface{}, toKindName string) (value interface{}, err error) {
+    if valueRaw == nil {
+        return r.zeroValue(toKindName)
+    }
+
+    fromType := reflect.TypeOf(valueRaw)
+
+    r.lock.RLock()
+
+    kd, found := r.kinds[toKindName]
+
+    var convert ConvertFunc
+    if len(r.conversions) > 0 {
+        convert = r.conversions[conversionKey{ fromType: fromType, kindName: toKindName }]
+    }
+
+    r.lock.RUnlock()
+
+    if found == false {
+        return nil, newParseError(toKindName, valueRaw, ErrUnsupportedKind, nil, fmt.Sprintf("no operation from type [%s] to kind [%s]", fromType, toKindName))
+    }
+
+    if convert != nil {
+        return convertE(convert, valueRaw, toKindName, kd.zeroType)
+    } else if kd.convert != nil {
+        return convertE(kd.convert, valueRaw, toKindName, kd.zeroType)
+    } else if kd.textType != nil {
+        return r.parseText(valueRaw, toKindName, kd.textType)
+    }
+
+    return r.parseWithParser(valueRaw, toKindName)
+}
+
+// convertE calls a ConvertFunc and checks that it produced a value of the
+// kind's type. A value of another type is only accepted if it converts to
+// the kind's type without loss (e.g. an int64 for an int32 kind that is in
+// range, or a string for a named string type). Panics are returned as
+// errors, except for runtime errors (see recoveredError()).
+func convertE(convert ConvertFunc, valueRaw interface{}, toKindName string, zeroType reflect.Type) (value interface{}, err error) {
+    defer func() {
+        if errRaw := recover(); errRaw != nil {
+            recovered := recoveredError(errRaw)
+
+            value = nil
+            err = newParseError(toKindName, valueRaw, classifyError(recovered), recovered, "")
+        }
+    }()
+
+    value, err = convert(valueRaw)
+    if err != nil {
+        return nil, newParseError(toKindName, valueRaw, classifyError(err), err, "")
+    }
+
+    if value == nil {
+        return reflect.Zero(zeroType).Interface(), nil
+    }
+
+    v := reflect.ValueOf(value)
+    if v.Type() == zeroType {
+        return value, nil
+    }
+
+    fromClass, _ := numericClass(v.Type())
+    toClass, _ := numericClass(zeroType)
+
+    // Signed and unsigned integers may be converted to each other, subject to
+    // the range check below.
+    if fromClass == reflect.Uint {
+        fromClass = reflect.Int
+    }
+
+    if toClass == reflect.Uint {
+        toClass = reflect.Int
+    }
+
+    if v.Type().ConvertibleTo(zeroType) == false || fromClass != toClass || (fromClass == reflect.Invalid && v.Kind() != zeroType.Kind()) {
+        return nil, newParseError(toKindName, valueRaw, ErrUnsupportedKind, nil, fmt.Sprintf("conversion to kind [%s] produced type [%s] rather than [%s]", toKindName, v.Type(), zeroType))
+    }
+
+    converted := v.Convert(zeroType)
+
+    // A number must survive the round-trip unchanged and keep its sign.
+    if fromClass != reflect.Invalid {
+        if converted.Convert(v.Type()).Interface() != value || isNegative(v) != isNegative(converted) {
+            return nil, newParseError(toKindName, valueRaw, ErrRange, nil, fmt.Sprintf("conversion to kind [%s] produced [%v], which does not fit [%s]", toKindName, value, zeroType))
+        }
+    }
+
+    return converted.Interface(), nil
+}
+
+// isNegative indicates whether a numeric value is less than zero.
+func isNegative(v reflect.Value) bool {
+    switch v.Kind() {
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+        return v.Int() < 0
+    case reflect.Float32, reflect.Float64:
+        return v.Float() < 0
+    }
+
+    return false
+}
+
+// parseWithParser parses a value to a built-in kind using the parser
+// registered for its type.
+func (r *Registry) parseWithParser(valueRaw interface{}, toKindName string) (value interface{}, err error) {
+    fromType := reflect.TypeOf(valueRaw)
+
+    pm, found := r.match(fromType)
+    if found == false {
+        return nil, newParseError(toKindName, valueRaw, ErrUnsupportedKind, nil, fmt.Sprintf("no parser registered for type [%s]", fromType))
+    }
+
+    valueRaw, isNil := pm.apply(valueRaw)
+    if isNil == true {
+        // A nil pointer is treated as a nil value.
+        return r.zeroValue(toKindName)
+    }
+
+    convert, found := pm.pe.converts[toKindName]
+    if found == false {
+        return nil, newParseError(toKindName, valueRaw, ErrUnsupportedKind, nil, fmt.Sprintf("parser [%T] does not support kind [%s]", pm.pe.p, toKindName))
+    }
+
+    defer func() {
+        if errRaw := recover(); errRaw != nil {
+            recovered := recoveredError(errRaw)
+
+            value = nil
+            err = newParseError(toKindName, valueRaw, classifyError(recovered), recovered, "")
+        }
+    }()
+
+    value, err = convert(valueRaw)
+    if err != nil {
+        return nil, newParseError(toKindName, valueRaw, classifyError(err), err, "")
+    }
+
+    return value, nil
+}
+
+// parseText parses a value to a kind registered with AddTextKind().
+func (r *Registry) parseText(valueRaw interface{}, toKindName string, t reflect.Type) (value interface{}, err error) {
+    s, err := r.ParseE(valueRaw, "string")
+    if err != nil {
+        return nil, err
+    }
+
+    value, err = unmarshalText(t, s.(string))
+    if err != nil {
+        return nil, newParseError(toKindName, valueRaw, classifyError(err), err, "")
+    }
+
+    return value, nil
+}
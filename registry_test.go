@@ -0,0 +1,370 @@
+package parse
+
+import (
+    "testing"
+    "errors"
+    "reflect"
+    "sort"
+    "sync"
+)
+
+// testStringParser is distinguishable from StringParser.
+type testStringParser struct {
+    StringParser
+}
+
+func TestGetParser_TypeIdentity(t *testing.T) {
+    // Two distinct types with the same name.
+    type1 := func() reflect.Type {
+        type Id string
+        return reflect.TypeOf(Id(""))
+    }()
+
+    type2 := func() reflect.Type {
+        type Id string
+        return reflect.TypeOf(Id(""))
+    }()
+
+    if type1.Name() != type2.Name() {
+        t.Fatalf("Test types should have the same name.")
+    }
+
+    r := NewRegistry()
+    r.AddParser(type1, new(testStringParser))
+
+    if p, found := r.lookupParser(type1); found == false {
+        t.Fatalf("Registered type not found.")
+    } else if _, ok := p.(*testStringParser); ok == false {
+        t.Fatalf("Registered parser not returned: [%v]", p)
+    }
+
+    // The other type only falls back to the string parser.
+    if p, found := r.lookupParser(type2); found == false {
+        t.Fatalf("Type with the same name did not fall back to its underlying type.")
+    } else if _, ok := p.(*testStringParser); ok == true {
+        t.Fatalf("Type with the same name should not match.")
+    }
+}
+
+func TestGetParser_UnnamedTypes(t *testing.T) {
+    if _, found := DefaultRegistry.lookupParser(reflect.TypeOf([]int {})); found == true {
+        t.Fatalf("Unnamed slice type should not be found.")
+    } else if _, found := DefaultRegistry.lookupParser(reflect.TypeOf(map[string]string {})); found == true {
+        t.Fatalf("Unnamed map type should not be found.")
+    }
+}
+
+func TestRegistry_Isolated(t *testing.T) {
+    type someType struct {}
+
+    r := NewRegistry()
+    r.AddParser(reflect.TypeOf(someType{}), NewStringParser())
+
+    if _, found := DefaultRegistry.lookupParser(reflect.TypeOf(someType{})); found == true {
+        t.Fatalf("Registration leaked into the default registry.")
+    }
+
+    if _, found := r.lookupParser(reflect.TypeOf(someType{})); found == false {
+        t.Fatalf("Registration not found in the isolated registry.")
+    }
+
+    // Built-in parsers are included.
+    value, err := r.ParseE("456", "uint64")
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if value != uint64(456) {
+        t.Fatalf("Parsed value not correct: [%v]", value)
+    }
+}
+
+func TestRegistry_Freeze(t *testing.T) {
+    r := NewRegistry()
+    r.Freeze()
+
+    if r.IsFrozen() != true {
+        t.Fatalf("Registry should be frozen.")
+    }
+
+    defer func() {
+        errRaw := recover()
+        if errRaw == nil {
+            t.Fatalf("Expected panic when registering with frozen registry.")
+        }
+
+        // go-logging wraps the error in a way that errors.Is() can not see
+        // through with every version of go-errors.
+        err := unwrapPanicError(errRaw.(error))
+        if errors.Is(err, ErrRegistryFrozen) == false {
+            t.Fatalf("Panic not correct: [%v]", err)
+        }
+    }()
+
+    r.AddParser(reflect.TypeOf(0), NewStringParser())
+}
+
+func TestRegistry_Clone(t *testing.T) {
+    type someType struct {}
+
+    r := NewRegistry()
+    r.Freeze()
+
+    clone := r.Clone()
+    if clone.IsFrozen() != false {
+        t.Fatalf("Clone should not be frozen.")
+    }
+
+    clone.AddParser(reflect.TypeOf(someType{}), NewStringParser())
+
+    if _, found := r.lookupParser(reflect.TypeOf(someType{})); found == true {
+        t.Fatalf("Registration leaked into the original registry.")
+    }
+}
+
+func TestRegistry_Concurrent(t *testing.T) {
+    r := NewRegistry()
+
+    wg := sync.WaitGroup{}
+
+    for i := 0; i < 10; i++ {
+        wg.Add(2)
+
+        go func() {
+            defer wg.Done()
+
+            type someType string
+            r.AddParser(reflect.TypeOf(someType("")), NewStringParser())
+        }()
+
+        go func() {
+            defer wg.Done()
+
+            if value := r.Parse("123", "uint64"); value != uint64(123) {
+                t.Errorf("Parsed value not correct: [%v]", value)
+            }
+        }()
+    }
+
+    wg.Wait()
+}
+
+func TestRegistry_UnderlyingKind(t *testing.T) {
+    type userId string
+    type rawValue []byte
+    type count int64
+
+    value, err := ParseE(userId("123"), "uint64")
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if value != uint64(123) {
+        t.Fatalf("Parsed value not correct: [%v]", value)
+    }
+
+    id := userId("456")
+
+    value, err = ParseE(&id, "uint64")
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if value != uint64(456) {
+        t.Fatalf("Parsed value not correct: [%v]", value)
+    }
+
+    if ut, found := underlyingType(reflect.TypeOf(rawValue {})); found == false || ut != bytesType {
+        t.Fatalf("Byte-slice type did not resolve to []byte: [%v]", ut)
+    } else if ut, found := underlyingType(reflect.TypeOf(count(0))); found == false || ut != reflect.TypeOf(int64(0)) {
+        t.Fatalf("Integer type did not resolve to int64: [%v]", ut)
+    } else if _, found := underlyingType(stringType); found == true {
+        t.Fatalf("Unnamed type should not resolve.")
+    } else if _, found := underlyingType(reflect.TypeOf([]string {})); found == true {
+        t.Fatalf("Non-byte slice should not resolve.")
+    }
+}
+
+func TestRegistry_UnderlyingKind_ExactMatchPreferred(t *testing.T) {
+    type userId string
+
+    r := NewRegistry()
+    r.AddParser(reflect.TypeOf(userId("")), new(testStringParser))
+
+    p, found := r.lookupParser(reflect.TypeOf(userId("")))
+    if found == false {
+        t.Fatalf("Registered type not found.")
+    } else if _, ok := p.(*testStringParser); ok == false {
+        t.Fatalf("Exact match was not preferred: [%v]", p)
+    }
+}
+
+// benchmarkInputs are valid string inputs for every built-in kind.
+var benchmarkInputs = map[string]string {
+    "string": "abc",
+    "int8": "-12",
+    "int16": "-1234",
+    "int32": "-123456",
+    "int64": "-1234567890",
+    "uint8": "12",
+    "uint16": "1234",
+    "uint32": "123456",
+    "uint64": "1234567890",
+    "hex8": "ff",
+    "hex16": "ff0f",
+    "hex32": "ff0f00aa",
+    "hex64": "ff0f00aaff0f00aa",
+    "float32": "1.5",
+    "float64": "-1.25",
+    "bool": "true",
+    "rfc3339": "2016-11-08T19:32:29Z",
+}
+
+func TestBenchmarkInputs(t *testing.T) {
+    for kindName := range NameMethodMap {
+        valueRaw, found := benchmarkInputs[kindName]
+        if found == false {
+            t.Fatalf("Kind [%s] has no benchmark input.", kindName)
+        } else if _, err := ParseE(valueRaw, kindName); err != nil {
+            t.Fatalf("Benchmark input for kind [%s] not valid: [%s]", kindName, err)
+        }
+    }
+}
+
+func TestParseE_Allocations(t *testing.T) {
+    // Only boxing the result may allocate.
+    allocs := testing.AllocsPerRun(100, func() {
+        ParseE("1234567890", "uint64")
+    })
+
+    if allocs > 1 {
+        t.Fatalf("Too many allocations: (%f)", allocs)
+    }
+}
+
+// BenchmarkParseE parses a string to every built-in kind.
+func BenchmarkParseE(b *testing.B) {
+    kindNames := make([]string, 0, len(NameMethodMap))
+    for kindName := range NameMethodMap {
+        kindNames = append(kindNames, kindName)
+    }
+
+    sort.Strings(kindNames)
+
+    for _, kindName := range kindNames {
+        var valueRaw interface{} = benchmarkInputs[kindName]
+
+        b.Run(kindName, func(b *testing.B) {
+            b.ReportAllocs()
+
+            for i := 0; i < b.N; i++ {
+                if _, err := ParseE(valueRaw, kindName); err != nil {
+                    b.Fatal(err)
+                }
+            }
+        })
+    }
+}
+
+// BenchmarkParseE_Numeric parses a decoded JSON number (a float64).
+func BenchmarkParseE_Numeric(b *testing.B) {
+    b.ReportAllocs()
+
+    for i := 0; i < b.N; i++ {
+        if _, err := ParseE(float64(1234567890), "uint64"); err != nil {
+            b.Fatal(err)
+        }
+    }
+}
+
+// BenchmarkParseE_UnderlyingType parses a named type, which falls back to
+// its underlying type.
+func BenchmarkParseE_UnderlyingType(b *testing.B) {
+    b.ReportAllocs()
+
+    for i := 0; i < b.N; i++ {
+        if _, err := ParseE(testAccountId(1234567890), "uint64"); err != nil {
+            b.Fatal(err)
+        }
+    }
+}
+
+func TestParseE_Nil_EveryKind(t *testing.T) {
+    var nilPointer *string
+
+    for kindName, zeroType := range KindNameZeroType {
+        for _, valueRaw := range []interface{} { nil, nilPointer } {
+            value, err := ParseE(valueRaw, kindName)
+            if err != nil {
+                t.Fatalf("Parse of nil to kind [%s] failed: [%s]", kindName, err)
+            } else if reflect.TypeOf(value) != zeroType {
+                t.Fatalf("Kind [%s] zero-value has wrong type: [%T]", kindName, value)
+            } else if reflect.ValueOf(value).IsZero() == false {
+                t.Fatalf("Kind [%s] value not zero: [%v]", kindName, value)
+            }
+        }
+    }
+
+    if v := Parse(nil, "uint64").(uint64); v != 0 {
+        t.Fatalf("Zero-value not correct: [%d]", v)
+    }
+}
+
+func TestParseE_Nil_InvalidKind(t *testing.T) {
+    _, err := ParseE(nil, "invalid-kind")
+    if errors.Is(err, ErrUnsupportedKind) == false {
+        t.Fatalf("Expected unsupported-kind error: [%v]", err)
+    }
+}
+
+func TestParseOrE(t *testing.T) {
+    value, present, err := ParseOrE("123", "uint64", uint64(10))
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if present != true || value.(uint64) != 123 {
+        t.Fatalf("Present value not correct: [%v] (%v)", value, present)
+    }
+
+    value, present, err = ParseOrE(nil, "uint64", uint64(10))
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if present != false || value.(uint64) != 10 {
+        t.Fatalf("Default not correct: [%v] (%v)", value, present)
+    }
+
+    var nilPointer *string
+
+    value, present, err = ParseOrE(nilPointer, "int32", 10)
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if present != false || value.(int32) != 10 {
+        t.Fatalf("Converted default not correct: [%v] (%v)", value, present)
+    }
+
+    value, present, err = ParseOrE(nil, "float64", "1.5")
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if present != false || value.(float64) != 1.5 {
+        t.Fatalf("Parsed default not correct: [%v] (%v)", value, present)
+    }
+
+    value, present, err = ParseOrE(nil, "bool", nil)
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if present != false || value.(bool) != false {
+        t.Fatalf("Zero-value not correct: [%v] (%v)", value, present)
+    }
+}
+
+func TestParseOrE_InvalidDefault(t *testing.T) {
+    _, _, err := ParseOrE(nil, "uint8", -1)
+    if errors.Is(err, ErrRange) == false {
+        t.Fatalf("Expected range error: [%v]", err)
+    }
+
+    _, _, err = ParseOrE(nil, "uint8", "abc")
+    if errors.Is(err, ErrSyntax) == false {
+        t.Fatalf("Expected syntax error: [%v]", err)
+    }
+}
+
+func TestParseOr(t *testing.T) {
+    value, present := ParseOr(nil, "string", "default")
+    if present != false || value.(string) != "default" {
+        t.Fatalf("Default not correct: [%v] (%v)", value, present)
+    }
+}
@@ -0,0 +1,329 @@
+package parse
+
+import (
+    "fmt"
+    "os"
+    "strings"
+
+    "net/http"
+    "net/url"
+
+    "github.com/dsoprea/go-logging"
+)
+
+var (
+    // missingMessages describe a required value that is absent, by source.
+    // These are the messages that the From* helpers have always produced.
+    missingMessages = map[string]string {
+        SourceBody: "regular body argument empty or omitted: [%s]",
+        SourceQuery: "query argument empty or omitted: [%s]",
+        SourceHeader: "HTTP header empty or omitted: [%s]",
+        SourceCookie: "cookie empty or omitted: [%s]",
+        SourceJson: "JSON body argument empty or omitted: [%s]",
+        SourceMap: "map key empty or absent: [%s]",
+        SourceEnviron: "environment argument empty or omitted: [%s]",
+        SourceChain: "value empty or omitted from every source: [%s]",
+    }
+)
+
+// Source is somewhere that raw values are looked up by name (e.g. a
+// request's query).
+type Source interface {
+    // Lookup returns the raw value for the name. `present` is false if the
+    // name was not found at all. A value that was found may still be empty.
+    Lookup(name string) (valueRaw interface{}, present bool)
+
+    // Describe returns the name of the source as reported by
+    // ParseError.Source() (e.g. SourceQuery).
+    Describe() string
+}
+
+// originSource is implemented by sources that are composed of others (e.g.
+// ChainSource) to report which one supplied a value.
+type originSource interface {
+    lookupOrigin(name string) (valueRaw interface{}, present bool, origin Origin)
+}
+
+// lookupOrigin looks the name up in the source and reports where the value
+// was found. If it is absent, the origin is the source itself.
+func lookupOrigin(source Source, name string) (valueRaw interface{}, present bool, origin Origin) {
+    if composite, ok := source.(originSource); ok == true {
+        return composite.lookupOrigin(name)
+    }
+
+    valueRaw, present = source.Lookup(name)
+
+    origin = Origin{
+        Source: source.Describe(),
+        Name: name,
+    }
+
+    return valueRaw, present, origin
+}
+
+// missingMessage describes a required value that is absent from the source.
+func missingMessage(source, name string) string {
+    if format, found := missingMessages[source]; found == true {
+        return fmt.Sprintf(format, name)
+    }
+
+    return fmt.Sprintf("%s value empty or omitted: [%s]", source, name)
+}
+
+// Get looks up the value in the source and parses it to the given kind. An
+// absent optional value produces nil (or the default; see WithDefault()). It
+// panics on failure.
+func Get(source Source, name string, kindName string, required bool, opts ...Option) (value interface{}) {
+    value, err := GetE(source, name, kindName, required, opts...)
+    log.PanicIf(err)
+
+    return value
+}
+
+// GetE is the error-returning variant of Get.
+func GetE(source Source, name string, kindName string, required bool, opts ...Option) (value interface{}, err error) {
+    valueRaw, present, origin := lookupOrigin(source, name)
+    return resolve(origin, kindName, required, valueRaw, present, opts)
+}
+
+// QuerySource looks up values in a request's query.
+type QuerySource struct {
+    values url.Values
+}
+
+// NewQuerySource returns a Source for the query of the request. The query is
+// only parsed once.
+func NewQuerySource(r *http.Request) Source {
+    return &QuerySource{
+        values: r.URL.Query(),
+    }
+}
+
+func (qs *QuerySource) Lookup(name string) (valueRaw interface{}, present bool) {
+    if qs.values.Has(name) == false {
+        return nil, false
+    }
+
+    return qs.values.Get(name), true
+}
+
+func (qs *QuerySource) Describe() string {
+    return SourceQuery
+}
+
+// BodySource looks up values in a request's form-encoded body.
+type BodySource struct {
+    r *http.Request
+}
+
+// NewBodySource returns a Source for the form-encoded body of the request.
+// As with http.Request.FormValue(), query arguments are also found.
+func NewBodySource(r *http.Request) Source {
+    return &BodySource{
+        r: r,
+    }
+}
+
+func (bs *BodySource) Lookup(name string) (valueRaw interface{}, present bool) {
+    // This parses the form if it has not been parsed already.
+    s := bs.r.FormValue(name)
+
+    if _, present := bs.r.Form[name]; present == false {
+        return nil, false
+    }
+
+    return s, true
+}
+
+func (bs *BodySource) Describe() string {
+    return SourceBody
+}
+
+// HeaderSource looks up values in a request's headers.
+type HeaderSource struct {
+    header http.Header
+}
+
+// NewHeaderSource returns a Source for the headers of the request.
+func NewHeaderSource(r *http.Request) Source {
+    return &HeaderSource{
+        header: r.Header,
+    }
+}
+
+func (hs *HeaderSource) Lookup(name string) (valueRaw interface{}, present bool) {
+    if len(hs.header.Values(name)) == 0 {
+        return nil, false
+    }
+
+    return hs.header.Get(name), true
+}
+
+func (hs *HeaderSource) Describe() string {
+    return SourceHeader
+}
+
+// CookieSource looks up values in a request's cookies.
+type CookieSource struct {
+    r *http.Request
+}
+
+// NewCookieSource returns a Source for the cookies of the request.
+func NewCookieSource(r *http.Request) Source {
+    return &CookieSource{
+        r: r,
+    }
+}
+
+func (cs *CookieSource) Lookup(name string) (valueRaw interface{}, present bool) {
+    c, err := cs.r.Cookie(name)
+    if err != nil {
+        return nil, false
+    }
+
+    return c.Value, true
+}
+
+func (cs *CookieSource) Describe() string {
+    return SourceCookie
+}
+
+// MapSource looks up values in a map of strings.
+type MapSource struct {
+    dict map[string]string
+}
+
+// NewMapSource returns a Source for the map.
+func NewMapSource(dict map[string]string) Source {
+    return &MapSource{
+        dict: dict,
+    }
+}
+
+func (ms *MapSource) Lookup(name string) (valueRaw interface{}, present bool) {
+    s, present := ms.dict[name]
+    if present == false {
+        return nil, false
+    }
+
+    return s, true
+}
+
+func (ms *MapSource) Describe() string {
+    return SourceMap
+}
+
+// InterfaceMapSource looks up values in a map of arbitrary values (e.g. a
+// decoded JSON object or configuration file).
+type InterfaceMapSource struct {
+    dict map[string]interface{}
+}
+
+// NewInterfaceMapSource returns a Source for the map. A name that is not a
+// key of the map is treated as a dotted path into nested maps (e.g. "db.host"
+// finds "host" in the map under "db").
+func NewInterfaceMapSource(dict map[string]interface{}) Source {
+    return &InterfaceMapSource{
+        dict: dict,
+    }
+}
+
+func (ims *InterfaceMapSource) Lookup(name string) (valueRaw interface{}, present bool) {
+    if valueRaw, present = ims.dict[name]; present == true {
+        return valueRaw, true
+    }
+
+    dict := ims.dict
+    parts := strings.Split(name, ".")
+
+    for i, part := range parts {
+        valueRaw, present = dict[part]
+        if present == false {
+            return nil, false
+        } else if i == len(parts) - 1 {
+            return valueRaw, true
+        }
+
+        dict, present = valueRaw.(map[string]interface{})
+        if present == false {
+            return nil, false
+        }
+    }
+
+    return nil, false
+}
+
+func (ims *InterfaceMapSource) Describe() string {
+    return SourceMap
+}
+
+// EnvironSource looks up values in the environment.
+type EnvironSource struct {
+    // prefixed is set if names are converted to prefixed environment
+    // variable names. See NewPrefixedEnvironSource().
+    prefixed bool
+    prefix string
+}
+
+// NewEnvironSource returns a Source for the environment. Names are the names
+// of the environment variables.
+func NewEnvironSource() Source {
+    return new(EnvironSource)
+}
+
+// NewPrefixedEnvironSource returns a Source for the environment that
+// converts names to upper-snake-case and prefixes them (e.g. "db.max-conns"
+// is "APP_DB_MAX_CONNS" for the prefix "APP"). This suits the keys that
+// Load() looks up. The prefix may be empty.
+func NewPrefixedEnvironSource(prefix string) Source {
+    return &EnvironSource{
+        prefixed: true,
+        prefix: prefix,
+    }
+}
+
+// environName converts a name to upper-snake-case and prefixes it.
+func environName(prefix, name string) string {
+    name = strings.ToUpper(name)
+    name = strings.NewReplacer(".", "_", "-", "_").Replace(name)
+
+    if prefix == "" {
+        return name
+    }
+
+    return strings.TrimSuffix(prefix, "_") + "_" + name
+}
+
+// variable returns the name of the environment variable for the name.
+func (es *EnvironSource) variable(name string) string {
+    if es.prefixed == false {
+        return name
+    }
+
+    return environName(es.prefix, name)
+}
+
+func (es *EnvironSource) lookupOrigin(name string) (valueRaw interface{}, present bool, origin Origin) {
+    variable := es.variable(name)
+
+    origin = Origin{
+        Source: SourceEnviron,
+        Name: variable,
+    }
+
+    s, present := os.LookupEnv(variable)
+    if present == false {
+        return nil, false, origin
+    }
+
+    return s, true, origin
+}
+
+func (es *EnvironSource) Lookup(name string) (valueRaw interface{}, present bool) {
+    valueRaw, present, _ = es.lookupOrigin(name)
+    return valueRaw, present
+}
+
+func (es *EnvironSource) Describe() string {
+    return SourceEnviron
+}
@@ -0,0 +1,137 @@
+package parse
+
+import (
+    "testing"
+    "errors"
+    "strings"
+
+    "net/http"
+)
+
+// testSource is a Source that is not included with the package.
+type testSource map[string]interface{}
+
+func (ts testSource) Lookup(name string) (valueRaw interface{}, present bool) {
+    valueRaw, present = ts[name]
+    return valueRaw, present
+}
+
+func (ts testSource) Describe() string {
+    return "custom"
+}
+
+func TestGetE_CustomSource(t *testing.T) {
+    ts := testSource{
+        "count": "12",
+        "bad": "abc",
+    }
+
+    if value := Get(ts, "count", "uint16", true); value != uint16(12) {
+        t.Fatalf("Value not correct: [%v]", value)
+    }
+
+    _, err := GetE(ts, "bad", "uint16", true)
+
+    var pe *ParseError
+    if errors.As(err, &pe) == false {
+        t.Fatalf("Expected parse error: [%v]", err)
+    } else if pe.Source() != "custom" || pe.Name() != "bad" {
+        t.Fatalf("Error not annotated correctly: [%s] [%s]", pe.Source(), pe.Name())
+    }
+
+    _, err = GetE(ts, "absent", "uint16", true)
+    if errors.Is(err, ErrMissing) == false {
+        t.Fatalf("Expected missing error: [%v]", err)
+    } else if err.Error() != "custom value empty or omitted: [absent]" {
+        t.Fatalf("Message not correct: [%s]", err)
+    }
+
+    if value, err := GetAsE[int](ts, "count", true); err != nil || value != 12 {
+        t.Fatalf("Typed value not correct: [%v] [%v]", value, err)
+    }
+}
+
+func TestSources_Lookup(t *testing.T) {
+    req, err := http.NewRequest("POST", "http://example.com?q=1&empty=", strings.NewReader("b=2"))
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+    req.Header.Set("X-H", "3")
+    req.AddCookie(&http.Cookie{ Name: "c", Value: "4" })
+
+    t.Setenv("MULTIPARSE_TEST_SOURCE", "5")
+
+    cases := []struct {
+        source Source
+        description string
+        name string
+        expected string
+    }{
+        { NewQuerySource(req), SourceQuery, "q", "1" },
+        { NewQuerySource(req), SourceQuery, "empty", "" },
+        { NewBodySource(req), SourceBody, "b", "2" },
+        { NewHeaderSource(req), SourceHeader, "x-h", "3" },
+        { NewCookieSource(req), SourceCookie, "c", "4" },
+        { NewEnvironSource(), SourceEnviron, "MULTIPARSE_TEST_SOURCE", "5" },
+        { NewPrefixedEnvironSource("MULTIPARSE"), SourceEnviron, "test.source", "5" },
+        { NewMapSource(map[string]string { "m": "6" }), SourceMap, "m", "6" },
+        { NewInterfaceMapSource(map[string]interface{} { "i": "7" }), SourceMap, "i", "7" },
+    }
+
+    for _, c := range cases {
+        if c.source.Describe() != c.description {
+            t.Fatalf("Description not correct: [%s] != [%s]", c.source.Describe(), c.description)
+        }
+
+        valueRaw, present := c.source.Lookup(c.name)
+        if present != true || valueRaw != c.expected {
+            t.Fatalf("Source [%s] value [%s] not correct: [%v] (%v)", c.description, c.name, valueRaw, present)
+        }
+
+        if valueRaw, present := c.source.Lookup("absent"); present != false || valueRaw != nil {
+            t.Fatalf("Source [%s] should not find absent name: [%v]", c.description, valueRaw)
+        }
+    }
+}
+
+func TestPrefixedEnvironSource(t *testing.T) {
+    t.Setenv("MULTIPARSE_TEST_DB_MAX_CONNS", "20")
+
+    origin := Origin{}
+
+    for _, prefix := range []string { "MULTIPARSE_TEST", "MULTIPARSE_TEST_" } {
+        es := NewPrefixedEnvironSource(prefix)
+
+        if value := Get(es, "db.max-conns", "uint16", true, WithOrigin(&origin)); value != uint16(20) {
+            t.Fatalf("Value not correct: [%v]", value)
+        } else if origin.Source != SourceEnviron || origin.Name != "MULTIPARSE_TEST_DB_MAX_CONNS" {
+            t.Fatalf("Origin not correct: %v", origin)
+        }
+    }
+
+    _, err := GetE(NewPrefixedEnvironSource("MULTIPARSE_TEST"), "db.host", "string", true)
+    if err == nil || err.Error() != "environment argument empty or omitted: [MULTIPARSE_TEST_DB_HOST]" {
+        t.Fatalf("Expected missing error for the variable: [%v]", err)
+    }
+
+    if name := environName("", "db.max-conns"); name != "DB_MAX_CONNS" {
+        t.Fatalf("Unprefixed name not correct: [%s]", name)
+    }
+}
+
+func TestJsonRequestParser_Source(t *testing.T) {
+    req, err := http.NewRequest("POST", "http://example.com", strings.NewReader(`{"id": 123}`))
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    var source Source = NewJsonRequestParser(req)
+
+    if value := Get(source, "id", "uint64", true); value != uint64(123) {
+        t.Fatalf("Value not correct: [%v]", value)
+    } else if source.Describe() != SourceJson {
+        t.Fatalf("Description not correct: [%s]", source.Describe())
+    }
+}
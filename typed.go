@@ -0,0 +1,186 @@
+package parse
+
+import (
+    "fmt"
+    "reflect"
+
+    "net/http"
+
+    "github.com/dsoprea/go-logging"
+)
+
+// kindNameFor determines the kind implied by T. See kindNameForType().
+func kindNameFor[T any]() (kindName string, err error) {
+    t := reflect.TypeOf((*T)(nil)).Elem()
+
+    kindName, found := kindNameForType(t)
+    if found == false {
+        return "", newParseError("", nil, ErrUnsupportedKind, nil, fmt.Sprintf("kind can not be inferred from type [%s]", t))
+    }
+
+    return kindName, nil
+}
+
+// asType converts a parsed value to T. This only differs from a type
+// assertion for named types (e.g. `type AccountId uint64`).
+func asType[T any](value interface{}) T {
+    var zero T
+    if value == nil {
+        return zero
+    }
+
+    if v, ok := value.(T); ok == true {
+        return v
+    }
+
+    t := reflect.TypeOf((*T)(nil)).Elem()
+    return reflect.ValueOf(value).Convert(t).Interface().(T)
+}
+
+// typed infers the kind from T, gets the value using that kind, and returns
+// it as T. If T can be unmarshaled from a string (see isTextType()), the
+// value is gotten as a "string" and unmarshaled.
+func typed[T any](get func(kindName string) (interface{}, error)) (value T, err error) {
+    t := reflect.TypeOf((*T)(nil)).Elem()
+    if isTextType(t) == true {
+        s, err := get("string")
+        if err != nil || s == nil {
+            return value, err
+        }
+
+        valueRaw, err := unmarshalText(t, s.(string))
+        if err != nil {
+            return value, newParseError("", s, classifyError(err), err, "")
+        }
+
+        return valueRaw.(T), nil
+    }
+
+    kindName, err := kindNameFor[T]()
+    if err != nil {
+        return value, err
+    }
+
+    valueRaw, err := get(kindName)
+    if err != nil {
+        return value, err
+    }
+
+    return asType[T](valueRaw), nil
+}
+
+// ParseAs parses the value to T, inferring the kind from T (e.g. "uint64"
+// for uint64 and "rfc3339" for time.Time). It panics on failure.
+func ParseAs[T any](valueRaw interface{}) T {
+    value, err := ParseAsE[T](valueRaw)
+    log.PanicIf(err)
+
+    return value
+}
+
+// ParseAsE is the error-returning variant of ParseAs. A nil value produces
+// the zero-value of T.
+func ParseAsE[T any](valueRaw interface{}) (value T, err error) {
+    return typed[T](func(kindName string) (interface{}, error) {
+        if valueRaw == nil {
+            return nil, nil
+        }
+
+        return ParseE(valueRaw, kindName)
+    })
+}
+
+// GetAs is the typed variant of Get.
+func GetAs[T any](source Source, name string, required bool, opts ...Option) T {
+    value, err := GetAsE[T](source, name, required, opts...)
+    log.PanicIf(err)
+
+    return value
+}
+
+// GetAsE is the error-returning variant of GetAs.
+func GetAsE[T any](source Source, name string, required bool, opts ...Option) (value T, err error) {
+    return typed[T](func(kindName string) (interface{}, error) {
+        return GetE(source, name, kindName, required, opts...)
+    })
+}
+
+// Query is the typed variant of FromRequestQuery. An absent optional value
+// produces the zero-value of T.
+func Query[T any](r *http.Request, name string, required bool, opts ...Option) T {
+    value, err := QueryE[T](r, name, required, opts...)
+    log.PanicIf(err)
+
+    return value
+}
+
+// QueryE is the error-returning variant of Query.
+func QueryE[T any](r *http.Request, name string, required bool, opts ...Option) (value T, err error) {
+    return GetAsE[T](NewQuerySource(r), name, required, opts...)
+}
+
+// Body is the typed variant of FromRequestBody.
+func Body[T any](r *http.Request, name string, required bool, opts ...Option) T {
+    value, err := BodyE[T](r, name, required, opts...)
+    log.PanicIf(err)
+
+    return value
+}
+
+// BodyE is the error-returning variant of Body.
+func BodyE[T any](r *http.Request, name string, required bool, opts ...Option) (value T, err error) {
+    return GetAsE[T](NewBodySource(r), name, required, opts...)
+}
+
+// Header is the typed variant of FromRequestHeader.
+func Header[T any](r *http.Request, name string, required bool, opts ...Option) T {
+    value, err := HeaderE[T](r, name, required, opts...)
+    log.PanicIf(err)
+
+    return value
+}
+
+// HeaderE is the error-returning variant of Header.
+func HeaderE[T any](r *http.Request, name string, required bool, opts ...Option) (value T, err error) {
+    return GetAsE[T](NewHeaderSource(r), name, required, opts...)
+}
+
+// Cookie is the typed variant of FromRequestCookie.
+func Cookie[T any](r *http.Request, name string, required bool, opts ...Option) T {
+    value, err := CookieE[T](r, name, required, opts...)
+    log.PanicIf(err)
+
+    return value
+}
+
+// CookieE is the error-returning variant of Cookie.
+func CookieE[T any](r *http.Request, name string, required bool, opts ...Option) (value T, err error) {
+    return GetAsE[T](NewCookieSource(r), name, required, opts...)
+}
+
+// Environ is the typed variant of FromEnviron.
+func Environ[T any](name string, required bool, opts ...Option) T {
+    value, err := EnvironE[T](name, required, opts...)
+    log.PanicIf(err)
+
+    return value
+}
+
+// EnvironE is the error-returning variant of Environ.
+func EnvironE[T any](name string, required bool, opts ...Option) (value T, err error) {
+    return GetAsE[T](NewEnvironSource(), name, required, opts...)
+}
+
+// Json is the typed variant of JsonRequestParser.Get. Go does not allow
+// type-parameters on methods.
+func Json[T any](jrp *JsonRequestParser, name string, required bool, opts ...Option) T {
+    value, err := JsonE[T](jrp, name, required, opts...)
+    log.PanicIf(err)
+
+    return value
+}
+
+// JsonE is the error-returning variant of Json.
+func JsonE[T any](jrp *JsonRequestParser, name string, required bool, opts ...Option) (value T, err error) {
+    return GetAsE[T](jrp, name, required, opts...)
+}
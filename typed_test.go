@@ -0,0 +1,91 @@
+package parse
+
+import (
+    "testing"
+    "errors"
+    "time"
+
+    "net/http"
+)
+
+func TestParseAs(t *testing.T) {
+    if v := ParseAs[uint64]("123"); v != 123 {
+        t.Fatalf("uint64 not correct: [%d]", v)
+    }
+
+    if v := ParseAs[float32]("1.5"); v != 1.5 {
+        t.Fatalf("float32 not correct: [%f]", v)
+    }
+
+    if v := ParseAs[bool]("true"); v != true {
+        t.Fatalf("bool not correct.")
+    }
+
+    if v := ParseAs[int]("-12"); v != -12 {
+        t.Fatalf("int not correct: [%d]", v)
+    }
+
+    if v := ParseAs[time.Time]("2016-11-08T19:32:29Z"); v.Equal(time.Date(2016, 11, 8, 19, 32, 29, 0, time.UTC)) == false {
+        t.Fatalf("time not correct: [%s]", v)
+    }
+
+    if v := ParseAs[testAccountId]("456"); v != testAccountId(456) {
+        t.Fatalf("Named type not correct: [%d]", v)
+    }
+}
+
+func TestParseAsE_Nil(t *testing.T) {
+    v, err := ParseAsE[uint64](nil)
+    if err != nil {
+        t.Fatalf("Parse failed: [%s]", err)
+    } else if v != 0 {
+        t.Fatalf("Expected zero-value: [%d]", v)
+    }
+}
+
+func TestParseAsE_Invalid(t *testing.T) {
+    _, err := ParseAsE[uint8]("300")
+    if errors.Is(err, ErrRange) == false {
+        t.Fatalf("Expected range error: [%v]", err)
+    }
+}
+
+func TestParseAsE_UnsupportedType(t *testing.T) {
+    _, err := ParseAsE[[]string]("abc")
+    if errors.Is(err, ErrUnsupportedKind) == false {
+        t.Fatalf("Expected unsupported-kind error: [%v]", err)
+    }
+}
+
+func TestQuery(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com?aa=123", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    if v := Query[uint32](req, "aa", true); v != 123 {
+        t.Fatalf("Value not correct: [%d]", v)
+    }
+
+    if v := Query[uint32](req, "bb", false); v != 0 {
+        t.Fatalf("Expected zero-value for absent optional argument: [%d]", v)
+    }
+
+    _, err = QueryE[uint32](req, "bb", true)
+    if errors.Is(err, ErrMissing) == false {
+        t.Fatalf("Expected missing error: [%v]", err)
+    }
+}
+
+func TestHeader(t *testing.T) {
+    req, err := http.NewRequest("GET", "http://example.com", nil)
+    if err != nil {
+        t.Fatalf("Could not fabricate request: [%s]", err)
+    }
+
+    req.Header.Set("X-Value", "-1.25")
+
+    if v := Header[float64](req, "X-Value", true); v != -1.25 {
+        t.Fatalf("Value not correct: [%f]", v)
+    }
+}